@@ -51,6 +51,11 @@ func (s *Server) allianceOverview(c *gin.Context) {
 		return
 	}
 
+	since, ok := parseRecordWindow(c)
+	if !ok {
+		return
+	}
+
 	var (
 		roster  *postgres.PlayerRosterStats
 		summary *postgres.AllianceBattleSummary
@@ -66,7 +71,7 @@ func (s *Server) allianceOverview(c *gin.Context) {
 	})
 	g.Go(func() error {
 		var err error
-		summary, err = s.postgres.GetAllianceBattleSummary(ctx, region, *player.AllianceName)
+		summary, err = s.postgres.GetAllianceBattleSummary(ctx, region, *player.AllianceName, since)
 		return err
 	})
 	g.Go(func() error {
@@ -1,8 +1,10 @@
 package api
 
 import (
+	"albionstats/internal/items"
 	"albionstats/internal/postgres"
 	"albionstats/internal/util"
+	"math"
 	"net/http"
 	"sort"
 	"strconv"
@@ -14,39 +16,65 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	maxBattleWindow    = 24 * time.Hour
+	maxBattlesInWindow = 100
+)
+
 type MergedBattleResponse struct {
-	Region        string
-	BattleIDs     []int64
-	StartTime     time.Time
-	EndTime       time.Time
-	TotalPlayers  int
-	TotalKills    int
-	TotalFame     int64
-	AllianceStats []*MergedAllianceStat  `json:"Alliances"`
-	GuildStats    []*MergedGuildStat     `json:"Guilds"`
-	PlayerStats   []*MergedPlayerStat    `json:"Players"`
-	Kills         []postgres.BattleKills `json:"Kills"`
+	Region          string
+	BattleIDs       []int64
+	StartTime       time.Time
+	EndTime         time.Time
+	TotalPlayers    int
+	TotalKills      int
+	TotalFame       int64
+	TotalSilverLost int64
+	AllianceStats   []*MergedAllianceStat `json:"Alliances"`
+	GuildStats      []*MergedGuildStat    `json:"Guilds"`
+	PlayerStats     []*MergedPlayerStat   `json:"Players"`
+	Kills           []BattleKill          `json:"Kills"`
+	Sides           []*MergedSideStat
+	WinningSide     *int16
+	Awards          []postgres.BattleAward
+}
+
+// BattleKill is a kill with the killer's and victim's weapons looked up in the item
+// catalogue.
+type BattleKill struct {
+	postgres.BattleKills
+	KillerWeaponInfo *items.Info
+	VictimWeaponInfo *items.Info
 }
 
 type MergedAllianceStat struct {
 	AllianceName string
+	Side         *int16
 	PlayerCount  int32
 	Kills        int32
 	Deaths       int32
 	KillFame     int64
 	DeathFame    int64
+	SilverLost   int64
 	IP           int32
 }
 
 type MergedGuildStat struct {
 	GuildName    string
 	AllianceName *string
+	Side         *int16
 	PlayerCount  int32
 	Kills        int32
 	Deaths       int32
+	Assists      int32
 	KillFame     int64
 	DeathFame    int64
+	SilverLost   int64
 	IP           int32
+	// KillParticipation is the percentage of their side's kills the guild took part
+	// in, as killer or assist.
+	KillParticipation float64
+	Composition       RoleComposition
 }
 
 type MergedPlayerStat struct {
@@ -55,12 +83,20 @@ type MergedPlayerStat struct {
 	AllianceName *string
 	Kills        int32
 	Deaths       int32
+	Assists      int32
 	KillFame     int64
 	DeathFame    int64
+	SilverLost   int64
 	IP           int32
 	Weapon       *string
+	WeaponInfo   *items.Info
 	Damage       int64
 	Heal         int64
+	// Best across the merged battles; MultiKills is summed
+	KillStreak    int32
+	MultiKills    int32
+	BestMultiKill int32
+	Score         float64
 }
 
 func (s *Server) battle(c *gin.Context) {
@@ -70,6 +106,17 @@ func (s *Server) battle(c *gin.Context) {
 		return
 	}
 
+	battleIDs, ok := parseBattleIDs(c)
+	if !ok {
+		return
+	}
+
+	s.mergedBattleReport(c, region, battleIDs)
+}
+
+// parseBattleIDs reads the comma-separated battleId path parameter, writing a 400
+// response and returning false if it is invalid.
+func parseBattleIDs(c *gin.Context) ([]int64, bool) {
 	battleIDStr := c.Param("battleId")
 	battleIDStrs := strings.Split(battleIDStr, ",")
 	var battleIDs []int64
@@ -77,27 +124,102 @@ func (s *Server) battle(c *gin.Context) {
 		id, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid battleId: " + s})
-			return
+			return nil, false
 		}
 		battleIDs = append(battleIDs, id)
 	}
 
 	if len(battleIDs) == 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "No battleIds provided"})
-		return
+		return nil, false
 	}
 
 	if len(battleIDs) > 20 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Maximum 20 battleIds allowed"})
+		return nil, false
+	}
+
+	return battleIDs, true
+}
+
+// battlesInWindow merges every battle that started within [from, to), optionally
+// limited to a zone, into a single report.
+func (s *Server) battlesInWindow(c *gin.Context) {
+	region := c.Param("region")
+	if !util.IsValidServer(region) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid region"})
 		return
 	}
 
+	from, err := parseTimeQuery(c, "from")
+	if err != nil || from == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from parameter (must be RFC3339)"})
+		return
+	}
+
+	to, err := parseTimeQuery(c, "to")
+	if err != nil || to == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to parameter (must be RFC3339)"})
+		return
+	}
+
+	if !from.Before(*to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be before to"})
+		return
+	}
+
+	if to.Sub(*from) > maxBattleWindow {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Time window must be at most 24 hours"})
+		return
+	}
+
+	totalPlayers, err := strconv.Atoi(c.DefaultQuery("totalPlayers", "0"))
+	if err != nil || totalPlayers < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid totalPlayers parameter"})
+		return
+	}
+
+	filter := postgres.BattleSummaryFilter{
+		MinTotalPlayers: totalPlayers,
+		From:            from,
+		To:              to,
+		Zone:            strings.TrimSpace(c.Query("zone")),
+	}
+
+	summaries, err := s.postgres.GetBattleSummariesByRegion(region, filter, nil, maxBattlesInWindow+1, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch battle data: " + err.Error()})
+		return
+	}
+
+	if len(summaries) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No battles found"})
+		return
+	}
+
+	if len(summaries) > maxBattlesInWindow {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "More than 100 battles in window, narrow the window or raise totalPlayers"})
+		return
+	}
+
+	battleIDs := make([]int64, 0, len(summaries))
+	for _, summary := range summaries {
+		battleIDs = append(battleIDs, summary.BattleID)
+	}
+
+	s.mergedBattleReport(c, region, battleIDs)
+}
+
+func (s *Server) mergedBattleReport(c *gin.Context, region string, battleIDs []int64) {
 	var (
 		summaries     []postgres.BattleSummary
 		allianceStats []postgres.BattleAllianceStats
 		guildStats    []postgres.BattleGuildStats
 		playerStats   []postgres.BattlePlayerStats
 		battleKills   []postgres.BattleKills
+		sides         []postgres.BattleSideAssignment
+		involvement   []postgres.KillInvolvement
+		awards        []postgres.BattleAward
 	)
 
 	g, ctx := errgroup.WithContext(c.Request.Context())
@@ -132,6 +254,24 @@ func (s *Server) battle(c *gin.Context) {
 		return err
 	})
 
+	g.Go(func() error {
+		var err error
+		sides, err = s.postgres.GetBattleSidesByIDs(ctx, region, battleIDs)
+		return err
+	})
+
+	g.Go(func() error {
+		var err error
+		involvement, err = s.postgres.GetBattleKillInvolvement(ctx, region, battleIDs)
+		return err
+	})
+
+	g.Go(func() error {
+		var err error
+		awards, err = s.postgres.GetBattleAwardsByIDs(ctx, region, battleIDs)
+		return err
+	})
+
 	if err := g.Wait(); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch battle data: " + err.Error()})
 		return
@@ -146,7 +286,23 @@ func (s *Server) battle(c *gin.Context) {
 	resp.AllianceStats = s.mergeAllianceStats(allianceStats, playerStats)
 	resp.GuildStats = s.mergeGuildStats(guildStats, playerStats)
 	resp.PlayerStats = s.mergePlayerStats(playerStats)
-	resp.Kills = battleKills
+	addSilverLost(&resp, playerStats)
+	resp.Kills = s.enrichBattleKills(battleKills)
+
+	merged := mergeBattleSides(sides)
+	for _, stat := range resp.AllianceStats {
+		if side, ok := merged.alliances[stat.AllianceName]; ok {
+			stat.Side = &side
+		}
+	}
+	for _, stat := range resp.GuildStats {
+		if side, ok := merged.sideOf(stat.AllianceName, &stat.GuildName); ok {
+			stat.Side = &side
+		}
+	}
+	resp.Sides, resp.WinningSide = buildSideStats(merged, playerStats, battleKills, s.playerRoles(playerStats))
+	addKillParticipation(resp.GuildStats, merged, battleKills, involvement)
+	resp.Awards = awards
 
 	c.JSON(http.StatusOK, resp)
 }
@@ -236,6 +392,13 @@ func (s *Server) mergeGuildStats(stats []postgres.BattleGuildStats, playerStats
 	}
 	calcMap := make(map[string]*groupCalc)
 	playerCounts := buildGuildPlayerCounts(playerStats)
+	compositions := s.buildGuildCompositions(playerStats)
+	assists := make(map[string]int32)
+	for _, stat := range playerStats {
+		if stat.GuildName != nil && stat.Assists != nil {
+			assists[*stat.GuildName] += *stat.Assists
+		}
+	}
 
 	for _, stat := range stats {
 		m, ok := mergedMap[stat.GuildName]
@@ -268,6 +431,10 @@ func (s *Server) mergeGuildStats(stats []postgres.BattleGuildStats, playerStats
 		if count, ok := playerCounts[name]; ok {
 			v.PlayerCount = count
 		}
+		v.Assists = assists[name]
+		if comp, ok := compositions[name]; ok {
+			v.Composition = *comp
+		}
 		merged = append(merged, v)
 	}
 
@@ -278,6 +445,69 @@ func (s *Server) mergeGuildStats(stats []postgres.BattleGuildStats, playerStats
 	return merged
 }
 
+// addSilverLost totals the silver lost by each alliance and guild, and by the whole
+// report, from their players' deaths.
+func addSilverLost(resp *MergedBattleResponse, playerStats []postgres.BattlePlayerStats) {
+	alliances := make(map[string]int64)
+	guilds := make(map[string]int64)
+	for _, stat := range playerStats {
+		if stat.SilverLost == nil {
+			continue
+		}
+		resp.TotalSilverLost += *stat.SilverLost
+		if stat.AllianceName != nil {
+			alliances[*stat.AllianceName] += *stat.SilverLost
+		}
+		if stat.GuildName != nil {
+			guilds[*stat.GuildName] += *stat.SilverLost
+		}
+	}
+
+	for _, stat := range resp.AllianceStats {
+		stat.SilverLost = alliances[stat.AllianceName]
+	}
+	for _, stat := range resp.GuildStats {
+		stat.SilverLost = guilds[stat.GuildName]
+	}
+}
+
+// addKillParticipation sets each guild's share of its side's kills. Guilds without a
+// side are measured against the kills of their alliance, or their own if they have none.
+func addKillParticipation(guilds []*MergedGuildStat, sides battleSides, kills []postgres.BattleKills, involvement []postgres.KillInvolvement) {
+	sideKills := make(map[int16]int)
+	teamKills := make(map[string]int)
+	for _, kill := range kills {
+		if side, ok := sides.sideOf(kill.KillerAlliance, kill.KillerGuild); ok {
+			sideKills[side]++
+		}
+		if kill.KillerAlliance != nil {
+			teamKills[*kill.KillerAlliance]++
+		} else if kill.KillerGuild != nil {
+			teamKills[*kill.KillerGuild]++
+		}
+	}
+
+	involved := make(map[string]int)
+	for _, i := range involvement {
+		involved[i.GuildName]++
+	}
+
+	for _, guild := range guilds {
+		var total int
+		switch {
+		case guild.Side != nil:
+			total = sideKills[*guild.Side]
+		case guild.AllianceName != nil && *guild.AllianceName != "":
+			total = teamKills[*guild.AllianceName]
+		default:
+			total = teamKills[guild.GuildName]
+		}
+		if total > 0 {
+			guild.KillParticipation = math.Min(100, 100*float64(involved[guild.GuildName])/float64(total))
+		}
+	}
+}
+
 func buildAlliancePlayerCounts(stats []postgres.BattlePlayerStats) map[string]int32 {
 	playerSets := make(map[string]map[string]struct{})
 	for _, stat := range stats {
@@ -358,19 +588,69 @@ func (s *Server) mergePlayerStats(stats []postgres.BattlePlayerStats) []*MergedP
 		if stat.Heal != nil {
 			m.Heal += *stat.Heal
 		}
+		if stat.Assists != nil {
+			m.Assists += *stat.Assists
+		}
+		if stat.SilverLost != nil {
+			m.SilverLost += *stat.SilverLost
+		}
+		if stat.KillStreak != nil && *stat.KillStreak > m.KillStreak {
+			m.KillStreak = *stat.KillStreak
+		}
+		if stat.MultiKills != nil {
+			m.MultiKills += *stat.MultiKills
+		}
+		if stat.BestMultiKill != nil && *stat.BestMultiKill > m.BestMultiKill {
+			m.BestMultiKill = *stat.BestMultiKill
+		}
 	}
 
 	merged := make([]*MergedPlayerStat, 0, len(mergedMap))
+	var totalIP, ipCount int64
 	for name, v := range mergedMap {
 		if ipMap[name].count > 0 {
 			v.IP = int32(ipMap[name].totalIP / ipMap[name].count)
+			totalIP += int64(v.IP)
+			ipCount++
 		}
+		v.WeaponInfo = s.items.LookupPtr(v.Weapon)
 		merged = append(merged, v)
 	}
 
+	var averageIP float64
+	if ipCount > 0 {
+		averageIP = float64(totalIP) / float64(ipCount)
+	}
+	for _, v := range merged {
+		v.Score = s.mvp.Score(util.BattlePerformance{
+			Kills:    v.Kills,
+			Assists:  v.Assists,
+			Deaths:   v.Deaths,
+			KillFame: v.KillFame,
+			Damage:   v.Damage,
+			Healing:  v.Heal,
+			IP:       v.IP,
+		}, averageIP)
+	}
+
 	sort.Slice(merged, func(i, j int) bool {
+		if merged[i].Score != merged[j].Score {
+			return merged[i].Score > merged[j].Score
+		}
 		return merged[i].Kills > merged[j].Kills
 	})
 
 	return merged
 }
+
+func (s *Server) enrichBattleKills(kills []postgres.BattleKills) []BattleKill {
+	enriched := make([]BattleKill, 0, len(kills))
+	for _, kill := range kills {
+		enriched = append(enriched, BattleKill{
+			BattleKills:      kill,
+			KillerWeaponInfo: s.items.LookupPtr(&kill.KillerWeapon),
+			VictimWeaponInfo: s.items.LookupPtr(&kill.VictimWeapon),
+		})
+	}
+	return enriched
+}
@@ -1,18 +1,53 @@
 package api
 
 import (
+	"albionstats/internal/postgres"
+	"context"
+	"encoding/base64"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"golang.org/x/sync/errgroup"
 )
 
+// nextCursorHeader carries the opaque cursor for the next page of battle boards.
+// It is omitted on the last page.
+const nextCursorHeader = "X-Next-Cursor"
+
+type BattleBoardResponse struct {
+	postgres.BattleSummary
+	Alliances []BattleSide
+	Guilds    []BattleSide
+	// Player is set on player boards: the player's own line in each battle
+	Player *postgres.BattlePlayerStats `json:",omitempty"`
+}
+
+// BattleSide is one alliance or guild that took part in a battle.
+type BattleSide struct {
+	Name         string
+	ID           *string
+	AllianceName *string `json:",omitempty"`
+	PlayerCount  int32
+	Kills        int32
+	Deaths       int32
+	KillFame     int64
+	DeathFame    *int64
+}
+
+const maxBattleBoardParticipantFilters = 5
+
 func (s *Server) battleSummaries(c *gin.Context) {
 	region := c.Param("region")
 
 	limitStr := c.DefaultQuery("limit", "20")
 	offsetStr := c.DefaultQuery("offset", "0")
 	totalPlayersStr := c.DefaultQuery("totalPlayers", "10")
+	minKillsStr := c.DefaultQuery("minKills", "0")
+	minFameStr := c.DefaultQuery("minFame", "0")
 
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil || limit <= 0 || limit > 50 {
@@ -26,13 +61,70 @@ func (s *Server) battleSummaries(c *gin.Context) {
 		return
 	}
 
+	cursor, err := decodeBattleCursor(c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor parameter"})
+		return
+	}
+	if cursor != nil && offset > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cursor and offset cannot be combined"})
+		return
+	}
+
 	totalPlayers, err := strconv.Atoi(totalPlayersStr)
 	if err != nil || totalPlayers < 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid totalPlayers parameter"})
 		return
 	}
 
-	summaries, err := s.postgres.GetBattleSummariesByRegion(region, limit, offset, totalPlayers)
+	minKills, err := strconv.Atoi(minKillsStr)
+	if err != nil || minKills < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid minKills parameter"})
+		return
+	}
+
+	minFame, err := strconv.ParseInt(minFameStr, 10, 64)
+	if err != nil || minFame < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid minFame parameter"})
+		return
+	}
+
+	from, err := parseTimeQuery(c, "from")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from parameter (must be RFC3339)"})
+		return
+	}
+
+	to, err := parseTimeQuery(c, "to")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to parameter (must be RFC3339)"})
+		return
+	}
+
+	if from != nil && to != nil && !from.Before(*to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be before to"})
+		return
+	}
+
+	alliances := nonEmptyQueryArray(c, "alliance")
+	guilds := nonEmptyQueryArray(c, "guild")
+	if len(alliances) > maxBattleBoardParticipantFilters || len(guilds) > maxBattleBoardParticipantFilters {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Maximum 5 alliance and 5 guild filters allowed"})
+		return
+	}
+
+	filter := postgres.BattleSummaryFilter{
+		MinTotalPlayers: totalPlayers,
+		MinTotalKills:   minKills,
+		MinTotalFame:    minFame,
+		From:            from,
+		To:              to,
+		Alliances:       alliances,
+		Guilds:          guilds,
+		Zone:            strings.TrimSpace(c.Query("zone")),
+	}
+
+	summaries, err := s.postgres.GetBattleSummariesByRegion(region, filter, cursor, limit, offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get battle summaries"})
 		return
@@ -42,7 +134,14 @@ func (s *Server) battleSummaries(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, summaries)
+	boards, err := s.buildBattleBoards(c.Request.Context(), region, summaries)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get battle summaries"})
+		return
+	}
+
+	setNextBattleCursor(c, summaries, limit)
+	c.JSON(http.StatusOK, boards)
 }
 
 func (s *Server) battleAllianceSummaries(c *gin.Context) {
@@ -65,13 +164,23 @@ func (s *Server) battleAllianceSummaries(c *gin.Context) {
 		return
 	}
 
+	cursor, err := decodeBattleCursor(c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor parameter"})
+		return
+	}
+	if cursor != nil && offset > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cursor and offset cannot be combined"})
+		return
+	}
+
 	playerCount, err := strconv.Atoi(playerCountStr)
 	if err != nil || playerCount < 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid playerCount parameter"})
 		return
 	}
 
-	summaries, err := s.postgres.GetBattleSummariesByAlliance(region, allianceName, playerCount, limit, offset)
+	summaries, err := s.postgres.GetBattleSummariesByAlliance(region, allianceName, playerCount, cursor, limit, offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get battle summaries"})
 		return
@@ -81,7 +190,14 @@ func (s *Server) battleAllianceSummaries(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, summaries)
+	boards, err := s.buildBattleBoards(c.Request.Context(), region, summaries)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get battle summaries"})
+		return
+	}
+
+	setNextBattleCursor(c, summaries, limit)
+	c.JSON(http.StatusOK, boards)
 }
 
 func (s *Server) battleGuildSummaries(c *gin.Context) {
@@ -104,13 +220,23 @@ func (s *Server) battleGuildSummaries(c *gin.Context) {
 		return
 	}
 
+	cursor, err := decodeBattleCursor(c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor parameter"})
+		return
+	}
+	if cursor != nil && offset > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cursor and offset cannot be combined"})
+		return
+	}
+
 	playerCount, err := strconv.Atoi(playerCountStr)
 	if err != nil || playerCount < 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid playerCount parameter"})
 		return
 	}
 
-	summaries, err := s.postgres.GetBattleSummariesByGuild(region, guildName, playerCount, limit, offset)
+	summaries, err := s.postgres.GetBattleSummariesByGuild(region, guildName, playerCount, cursor, limit, offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get battle summaries"})
 		return
@@ -120,7 +246,14 @@ func (s *Server) battleGuildSummaries(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, summaries)
+	boards, err := s.buildBattleBoards(c.Request.Context(), region, summaries)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get battle summaries"})
+		return
+	}
+
+	setNextBattleCursor(c, summaries, limit)
+	c.JSON(http.StatusOK, boards)
 }
 
 func (s *Server) battlePlayerSummaries(c *gin.Context) {
@@ -143,13 +276,23 @@ func (s *Server) battlePlayerSummaries(c *gin.Context) {
 		return
 	}
 
+	cursor, err := decodeBattleCursor(c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor parameter"})
+		return
+	}
+	if cursor != nil && offset > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cursor and offset cannot be combined"})
+		return
+	}
+
 	playerCount, err := strconv.Atoi(playerCountStr)
 	if err != nil || playerCount < 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid playerCount parameter"})
 		return
 	}
 
-	summaries, err := s.postgres.GetBattleSummariesByPlayer(region, playerName, playerCount, limit, offset)
+	summaries, err := s.postgres.GetBattleSummariesByPlayer(region, playerName, playerCount, cursor, limit, offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get battle summaries"})
 		return
@@ -159,5 +302,162 @@ func (s *Server) battlePlayerSummaries(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, summaries)
+	boards, err := s.buildBattleBoards(c.Request.Context(), region, summaries)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get battle summaries"})
+		return
+	}
+
+	battleIDs := make([]int64, 0, len(summaries))
+	for _, summary := range summaries {
+		battleIDs = append(battleIDs, summary.BattleID)
+	}
+	playerStats, err := s.postgres.GetPlayerBattleStatsByIDs(c.Request.Context(), region, playerName, battleIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get player battle stats"})
+		return
+	}
+	statsByBattle := make(map[int64]*postgres.BattlePlayerStats, len(playerStats))
+	for i := range playerStats {
+		statsByBattle[playerStats[i].BattleID] = &playerStats[i]
+	}
+	for i := range boards {
+		boards[i].Player = statsByBattle[boards[i].BattleID]
+	}
+
+	setNextBattleCursor(c, summaries, limit)
+	c.JSON(http.StatusOK, boards)
+}
+
+// buildBattleBoards attaches the alliances and guilds of each battle, largest first.
+func (s *Server) buildBattleBoards(ctx context.Context, region string, summaries []postgres.BattleSummary) ([]BattleBoardResponse, error) {
+	battleIDs := make([]int64, 0, len(summaries))
+	for _, summary := range summaries {
+		battleIDs = append(battleIDs, summary.BattleID)
+	}
+
+	var (
+		allianceStats []postgres.BattleAllianceStats
+		guildStats    []postgres.BattleGuildStats
+	)
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		allianceStats, err = s.postgres.GetBattleAllianceStatsByIDs(ctx, region, battleIDs)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		guildStats, err = s.postgres.GetBattleGuildStatsByIDs(ctx, region, battleIDs)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	alliances := make(map[int64][]BattleSide)
+	for _, stat := range allianceStats {
+		alliances[stat.BattleID] = append(alliances[stat.BattleID], BattleSide{
+			Name:        stat.AllianceName,
+			ID:          stat.AllianceID,
+			PlayerCount: stat.PlayerCount,
+			Kills:       stat.Kills,
+			Deaths:      stat.Deaths,
+			KillFame:    stat.KillFame,
+			DeathFame:   stat.DeathFame,
+		})
+	}
+
+	guilds := make(map[int64][]BattleSide)
+	for _, stat := range guildStats {
+		guilds[stat.BattleID] = append(guilds[stat.BattleID], BattleSide{
+			Name:         stat.GuildName,
+			ID:           stat.GuildID,
+			AllianceName: stat.AllianceName,
+			PlayerCount:  stat.PlayerCount,
+			Kills:        stat.Kills,
+			Deaths:       stat.Deaths,
+			KillFame:     stat.KillFame,
+			DeathFame:    stat.DeathFame,
+		})
+	}
+
+	boards := make([]BattleBoardResponse, 0, len(summaries))
+	for _, summary := range summaries {
+		boards = append(boards, BattleBoardResponse{
+			BattleSummary: summary,
+			Alliances:     alliances[summary.BattleID],
+			Guilds:        guilds[summary.BattleID],
+		})
+	}
+	return boards, nil
+}
+
+// parseTimeQuery reads an optional RFC3339 timestamp from the query string.
+func parseTimeQuery(c *gin.Context, key string) (*time.Time, error) {
+	val := strings.TrimSpace(c.Query(key))
+	if val == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, val)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// nonEmptyQueryArray returns every non-blank value of a repeated query parameter.
+func nonEmptyQueryArray(c *gin.Context, key string) []string {
+	var values []string
+	for _, val := range c.QueryArray(key) {
+		if val = strings.TrimSpace(val); val != "" {
+			values = append(values, val)
+		}
+	}
+	return values
+}
+
+func encodeBattleCursor(summary postgres.BattleSummary) string {
+	raw := fmt.Sprintf("%d:%d", summary.StartTime.UnixMicro(), summary.BattleID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeBattleCursor(cursor string) (*postgres.BattleCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+
+	startTime, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	battleID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &postgres.BattleCursor{
+		StartTime: time.UnixMicro(startTime).UTC(),
+		BattleID:  battleID,
+	}, nil
+}
+
+// setNextBattleCursor points the client at the page after summaries, if it was full.
+func setNextBattleCursor(c *gin.Context, summaries []postgres.BattleSummary, limit int) {
+	if len(summaries) < limit {
+		return
+	}
+	c.Header(nextCursorHeader, encodeBattleCursor(summaries[len(summaries)-1]))
 }
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"albionstats/internal/items"
+	"albionstats/internal/postgres"
+)
+
+// RoleComposition counts players by the role of their weapon. Players whose weapon
+// is unknown or isn't a weapon count as Unknown.
+type RoleComposition struct {
+	Tank    int32
+	Healer  int32
+	Support int32
+	Melee   int32
+	Ranged  int32
+	Unknown int32
+}
+
+// AverageComposition is RoleComposition averaged over a guild's recent battles.
+type AverageComposition struct {
+	Battles int
+	Tank    float64
+	Healer  float64
+	Support float64
+	Melee   float64
+	Ranged  float64
+	Unknown float64
+}
+
+func (r *RoleComposition) add(role string) {
+	switch role {
+	case items.RoleTank:
+		r.Tank++
+	case items.RoleHealer:
+		r.Healer++
+	case items.RoleSupport:
+		r.Support++
+	case items.RoleMelee:
+		r.Melee++
+	case items.RoleRanged:
+		r.Ranged++
+	default:
+		r.Unknown++
+	}
+}
+
+func (s *Server) weaponRole(weapon *string) string {
+	if weapon == nil || *weapon == "" {
+		return ""
+	}
+	return s.items.Role(*weapon)
+}
+
+// playerRoles picks one role per player across the merged battles, from the first
+// weapon they were seen with.
+func (s *Server) playerRoles(stats []postgres.BattlePlayerStats) map[string]string {
+	roles := make(map[string]string)
+	for _, stat := range stats {
+		if role, ok := roles[stat.PlayerName]; ok && role != "" {
+			continue
+		}
+		roles[stat.PlayerName] = s.weaponRole(stat.Weapon)
+	}
+	return roles
+}
+
+// buildGuildCompositions counts each guild's players by role.
+func (s *Server) buildGuildCompositions(stats []postgres.BattlePlayerStats) map[string]*RoleComposition {
+	roles := s.playerRoles(stats)
+	counted := make(map[string]bool)
+	comps := make(map[string]*RoleComposition)
+	for _, stat := range stats {
+		if stat.GuildName == nil || *stat.GuildName == "" || counted[stat.PlayerName] {
+			continue
+		}
+		counted[stat.PlayerName] = true
+
+		comp, ok := comps[*stat.GuildName]
+		if !ok {
+			comp = &RoleComposition{}
+			comps[*stat.GuildName] = comp
+		}
+		comp.add(roles[stat.PlayerName])
+	}
+	return comps
+}
+
+// averageComposition averages a guild's per-battle compositions.
+func (s *Server) averageComposition(weapons []postgres.PlayerBattleWeapon) *AverageComposition {
+	perBattle := make(map[int64]*RoleComposition)
+	for _, w := range weapons {
+		comp, ok := perBattle[w.BattleID]
+		if !ok {
+			comp = &RoleComposition{}
+			perBattle[w.BattleID] = comp
+		}
+		comp.add(s.weaponRole(w.Weapon))
+	}
+
+	avg := &AverageComposition{Battles: len(perBattle)}
+	if avg.Battles == 0 {
+		return avg
+	}
+	for _, comp := range perBattle {
+		avg.Tank += float64(comp.Tank)
+		avg.Healer += float64(comp.Healer)
+		avg.Support += float64(comp.Support)
+		avg.Melee += float64(comp.Melee)
+		avg.Ranged += float64(comp.Ranged)
+		avg.Unknown += float64(comp.Unknown)
+	}
+	n := float64(avg.Battles)
+	avg.Tank /= n
+	avg.Healer /= n
+	avg.Support /= n
+	avg.Melee /= n
+	avg.Ranged /= n
+	avg.Unknown /= n
+	return avg
+}
@@ -0,0 +1,277 @@
+package api
+
+import (
+	"albionstats/internal/items"
+	"albionstats/internal/postgres"
+	"albionstats/internal/tasks"
+	"albionstats/internal/util"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	maxDeathRecaps = 50
+	deathsCacheTTL = 2 * time.Minute
+	// deathsCacheSize bounds how many players' deaths are kept at once
+	deathsCacheSize = 1000
+)
+
+// deathsCache keeps recent game API responses per player. The death recap shares the
+// region's rate limit with the pollers, so repeat views are served from here.
+type deathsCache struct {
+	mu      sync.Mutex
+	entries map[string]deathsCacheEntry
+}
+
+type deathsCacheEntry struct {
+	events  []tasks.Event
+	expires time.Time
+}
+
+func newDeathsCache() *deathsCache {
+	return &deathsCache{entries: make(map[string]deathsCacheEntry)}
+}
+
+func (c *deathsCache) get(key string, now time.Time) ([]tasks.Event, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || now.After(entry.expires) {
+		return nil, false
+	}
+	return entry.events, true
+}
+
+func (c *deathsCache) put(key string, events []tasks.Event, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= deathsCacheSize {
+		for k, entry := range c.entries {
+			if now.After(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	// Still full of live entries: make room by dropping any one
+	for k := range c.entries {
+		if len(c.entries) < deathsCacheSize {
+			break
+		}
+		delete(c.entries, k)
+	}
+	c.entries[key] = deathsCacheEntry{events: events, expires: now.Add(deathsCacheTTL)}
+}
+
+type DeathRecapResponse struct {
+	PlayerID   string
+	PlayerName string
+	Deaths     []DeathRecap
+}
+
+// DeathRecap is one death with who was involved and what it cost. BattleID is 0 for
+// deaths outside a battle, and the zone is only known for battle deaths.
+type DeathRecap struct {
+	EventID      int64
+	BattleID     int64
+	Timestamp    time.Time
+	KillArea     string
+	ZoneName     *string
+	ZoneType     *string
+	Fame         int64
+	SilverLost   int64
+	VictimIP     int32
+	VictimBuild  map[string]LoadoutItem
+	Killer       DeathRecapPlayer
+	KillerBuild  map[string]LoadoutItem
+	Participants []DeathRecapParticipant
+	GroupMembers []DeathRecapPlayer
+}
+
+type DeathRecapPlayer struct {
+	Name         string
+	GuildName    string
+	AllianceName string
+	IP           int32
+	WeaponInfo   items.Info
+}
+
+// DeathRecapParticipant is a player who dealt damage or healing towards the kill, with
+// their share of the total.
+type DeathRecapParticipant struct {
+	DeathRecapPlayer
+	Damage       int64
+	Healing      int64
+	DamageShare  float64
+	HealingShare float64
+}
+
+// playerDeaths fetches a player's latest deaths from the game API and breaks each one
+// down by killer, participants and the killer's group.
+func (s *Server) playerDeaths(c *gin.Context) {
+	server := c.Param("server")
+	if !util.IsValidServer(server) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid server. Must be one of: americas, europe, asia"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 || limit > maxDeathRecaps {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter (must be 1-50)"})
+		return
+	}
+
+	player, err := s.postgres.GetPlayerByName(c.Request.Context(), postgres.Region(server), c.Param("name"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database query failed"})
+		return
+	}
+
+	// Always fetch the most deaths so one cached entry serves every limit
+	cacheKey := server + ":" + player.PlayerID
+	events, ok := s.deaths.get(cacheKey, time.Now())
+	if !ok {
+		events, err = s.apiClient.FetchPlayerDeaths(c.Request.Context(), server, player.PlayerID, maxDeathRecaps)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Player deaths not found"})
+				return
+			}
+			s.logger.Warn("fetch player deaths failed", "err", err, "player_id", player.PlayerID)
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to fetch player deaths"})
+			return
+		}
+		s.deaths.put(cacheKey, events, time.Now())
+	}
+	if len(events) > limit {
+		events = events[:limit]
+	}
+
+	// Standalone kills come back with their own event id as the battle id
+	battleIDs := make([]int64, 0, len(events))
+	for _, ev := range events {
+		if ev.BattleID != 0 && ev.BattleID != ev.EventID {
+			battleIDs = append(battleIDs, ev.BattleID)
+		}
+	}
+
+	summaries := make(map[int64]postgres.BattleSummary)
+	if len(battleIDs) > 0 {
+		found, err := s.postgres.GetBattleSummariesByIDs(c.Request.Context(), server, battleIDs)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch battle summaries"})
+			return
+		}
+		for _, summary := range found {
+			summaries[summary.BattleID] = summary
+		}
+	}
+
+	resp := DeathRecapResponse{
+		PlayerID:   player.PlayerID,
+		PlayerName: player.Name,
+		Deaths:     make([]DeathRecap, 0, len(events)),
+	}
+	for _, ev := range events {
+		recap := s.buildDeathRecap(ev)
+		if summary, ok := summaries[ev.BattleID]; ok {
+			recap.BattleID = summary.BattleID
+			recap.ZoneName = summary.ZoneName
+			recap.ZoneType = summary.ZoneType
+		}
+		resp.Deaths = append(resp.Deaths, recap)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) buildDeathRecap(ev tasks.Event) DeathRecap {
+	recap := DeathRecap{
+		EventID:      ev.EventID,
+		Timestamp:    ev.TimeStamp,
+		KillArea:     ev.KillArea,
+		Fame:         ev.TotalVictimKillFame,
+		SilverLost:   ev.Victim.SilverLost(s.prices),
+		VictimIP:     int32(ev.Victim.AverageItemPower),
+		VictimBuild:  s.eventBuild(ev.Victim),
+		Killer:       s.deathRecapPlayer(ev.Killer),
+		KillerBuild:  s.eventBuild(ev.Killer),
+		Participants: make([]DeathRecapParticipant, 0, len(ev.Participants)),
+		GroupMembers: make([]DeathRecapPlayer, 0, len(ev.GroupMembers)),
+	}
+	// Standalone kills come back with their own event id as the battle id
+	if ev.BattleID != ev.EventID {
+		recap.BattleID = ev.BattleID
+	}
+
+	var totalDamage, totalHealing float64
+	for _, part := range ev.Participants {
+		totalDamage += part.DamageDone
+		totalHealing += part.SupportHealingDone
+	}
+	for _, part := range ev.Participants {
+		participant := DeathRecapParticipant{
+			DeathRecapPlayer: s.deathRecapPlayer(part),
+			Damage:           int64(part.DamageDone),
+			Healing:          int64(part.SupportHealingDone),
+		}
+		if totalDamage > 0 {
+			participant.DamageShare = part.DamageDone / totalDamage
+		}
+		if totalHealing > 0 {
+			participant.HealingShare = part.SupportHealingDone / totalHealing
+		}
+		recap.Participants = append(recap.Participants, participant)
+	}
+	sort.SliceStable(recap.Participants, func(i, j int) bool {
+		return recap.Participants[i].Damage > recap.Participants[j].Damage
+	})
+
+	for _, member := range ev.GroupMembers {
+		recap.GroupMembers = append(recap.GroupMembers, s.deathRecapPlayer(member))
+	}
+	return recap
+}
+
+func (s *Server) deathRecapPlayer(participant tasks.Participant) DeathRecapPlayer {
+	player := DeathRecapPlayer{
+		Name:         participant.Name,
+		GuildName:    participant.GuildName,
+		AllianceName: participant.AllianceName,
+		IP:           int32(participant.AverageItemPower),
+	}
+	if mh := participant.Equipment["MainHand"]; mh != nil {
+		player.WeaponInfo = s.items.Lookup(mh.Type)
+	}
+	return player
+}
+
+// eventBuild lists a participant's equipment by slot. Group members and assisting
+// participants often come back without equipment, so it is only used for the killer
+// and the victim.
+func (s *Server) eventBuild(participant tasks.Participant) map[string]LoadoutItem {
+	build := make(map[string]LoadoutItem, len(participant.Equipment))
+	for slot, item := range participant.Equipment {
+		if item == nil {
+			continue
+		}
+		build[slot] = LoadoutItem{
+			Info:    s.items.Lookup(item.Type),
+			Quality: int16(item.Quality),
+			Count:   item.Count,
+		}
+	}
+	return build
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"albionstats/internal/postgres"
+	"albionstats/internal/util"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FightResponse struct {
+	Region    string
+	FightID   int64
+	BattleIDs []int64
+	Battles   []postgres.BattleSummary
+}
+
+// fight suggests the battles that belong to the same fight as battleId. A battle that
+// hasn't been grouped with any other is returned as a fight of its own.
+func (s *Server) fight(c *gin.Context) {
+	region := c.Param("region")
+	if !util.IsValidServer(region) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid region"})
+		return
+	}
+
+	battleID, err := strconv.ParseInt(c.Param("battleId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid battleId"})
+		return
+	}
+
+	fightID, battleIDs, err := s.postgres.GetFightBattleIDs(c.Request.Context(), region, battleID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get fight"})
+		return
+	}
+	if len(battleIDs) == 0 {
+		fightID = battleID
+		battleIDs = []int64{battleID}
+	}
+
+	summaries, err := s.postgres.GetBattleSummariesByIDs(c.Request.Context(), region, battleIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get fight"})
+		return
+	}
+	if len(summaries) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Battle not found"})
+		return
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].StartTime.Before(summaries[j].StartTime)
+	})
+
+	resp := FightResponse{
+		Region:    region,
+		FightID:   fightID,
+		BattleIDs: make([]int64, 0, len(summaries)),
+		Battles:   summaries,
+	}
+	for _, summary := range summaries {
+		resp.BattleIDs = append(resp.BattleIDs, summary.BattleID)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
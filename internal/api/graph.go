@@ -0,0 +1,147 @@
+package api
+
+import (
+	"albionstats/internal/postgres"
+	"albionstats/internal/util"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	edgeTypeAlly  = "ally"
+	edgeTypeEnemy = "enemy"
+)
+
+type AllianceGraphResponse struct {
+	Region    string
+	WeekStart time.Time
+	WeekEnd   time.Time
+	Nodes     []postgres.AllianceGraphNode
+	Edges     []AllianceGraphEdge
+}
+
+// AllianceGraphEdge is an ally edge weighted by battles fought on the same side, or
+// an enemy edge weighted by kills traded.
+type AllianceGraphEdge struct {
+	Source string
+	Target string
+	Type   string
+	Weight int64
+	Fame   int64
+}
+
+// allianceGraph builds the ally/enemy graph between the most active alliances of one
+// week. Weeks start on Monday 00:00 UTC.
+func (s *Server) allianceGraph(c *gin.Context) {
+	region := c.Param("region")
+	if !util.IsValidServer(region) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid region"})
+		return
+	}
+
+	week := time.Now().UTC()
+	if weekStr := c.Query("week"); weekStr != "" {
+		var err error
+		week, err = time.Parse(time.DateOnly, weekStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid week parameter (must be YYYY-MM-DD)"})
+			return
+		}
+	}
+	from := weekStart(week)
+	to := from.AddDate(0, 0, 7)
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter (must be 1-200)"})
+		return
+	}
+
+	minWeight, err := strconv.ParseInt(c.DefaultQuery("minWeight", "2"), 10, 64)
+	if err != nil || minWeight < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid minWeight parameter"})
+		return
+	}
+
+	var (
+		nodes      []postgres.AllianceGraphNode
+		allyEdges  []postgres.AllianceGraphEdge
+		enemyEdges []postgres.AllianceGraphEdge
+	)
+
+	g, ctx := errgroup.WithContext(c.Request.Context())
+	g.Go(func() error {
+		var err error
+		nodes, err = s.postgres.GetAllianceGraphNodes(ctx, region, from, to, limit)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		allyEdges, err = s.postgres.GetAllianceAllyEdges(ctx, region, from, to)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		enemyEdges, err = s.postgres.GetAllianceEnemyEdges(ctx, region, from, to)
+		return err
+	})
+
+	if err := g.Wait(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build alliance graph"})
+		return
+	}
+
+	inGraph := make(map[string]struct{}, len(nodes))
+	for _, node := range nodes {
+		inGraph[node.AllianceName] = struct{}{}
+	}
+
+	edges := make([]AllianceGraphEdge, 0)
+	addEdges := func(edgeType string, candidates []postgres.AllianceGraphEdge) {
+		for _, e := range candidates {
+			if e.Weight < minWeight {
+				continue
+			}
+			if _, ok := inGraph[e.Source]; !ok {
+				continue
+			}
+			if _, ok := inGraph[e.Target]; !ok {
+				continue
+			}
+			edges = append(edges, AllianceGraphEdge{
+				Source: e.Source,
+				Target: e.Target,
+				Type:   edgeType,
+				Weight: e.Weight,
+				Fame:   e.Fame,
+			})
+		}
+	}
+	addEdges(edgeTypeAlly, allyEdges)
+	addEdges(edgeTypeEnemy, enemyEdges)
+
+	sort.Slice(edges, func(i, j int) bool {
+		return edges[i].Weight > edges[j].Weight
+	})
+
+	c.JSON(http.StatusOK, AllianceGraphResponse{
+		Region:    region,
+		WeekStart: from,
+		WeekEnd:   to,
+		Nodes:     nodes,
+		Edges:     edges,
+	})
+}
+
+// weekStart returns Monday 00:00 UTC of the week containing t.
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
@@ -5,6 +5,7 @@ import (
 	"albionstats/internal/util"
 	"errors"
 	"net/http"
+	"strconv"
 
 	"golang.org/x/sync/errgroup"
 
@@ -17,8 +18,11 @@ type GuildOverviewResponse struct {
 	RosterStats   *postgres.PlayerRosterStats  `json:"RosterStats"`
 	BattleSummary *postgres.GuildBattleSummary `json:"BattleSummary"`
 	Players       []postgres.GuildPlayerStats  `json:"Players"`
+	Composition   *AverageComposition          `json:"Composition"`
 }
 
+const maxCompositionBattles = 50
+
 func (s *Server) guildOverview(c *gin.Context) {
 	region := c.Param("server")
 	if !util.IsValidServer(region) {
@@ -42,10 +46,28 @@ func (s *Server) guildOverview(c *gin.Context) {
 		return
 	}
 
+	since, ok := parseRecordWindow(c)
+	if !ok {
+		return
+	}
+
+	compBattles, err := strconv.Atoi(c.DefaultQuery("compBattles", "10"))
+	if err != nil || compBattles <= 0 || compBattles > maxCompositionBattles {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid compBattles parameter (must be 1-50)"})
+		return
+	}
+
+	compPlayerCount, err := strconv.Atoi(c.DefaultQuery("compPlayerCount", "5"))
+	if err != nil || compPlayerCount < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid compPlayerCount parameter"})
+		return
+	}
+
 	var (
 		roster  *postgres.PlayerRosterStats
 		summary *postgres.GuildBattleSummary
 		players []postgres.GuildPlayerStats
+		weapons []postgres.PlayerBattleWeapon
 	)
 
 	g, ctx := errgroup.WithContext(c.Request.Context())
@@ -56,7 +78,7 @@ func (s *Server) guildOverview(c *gin.Context) {
 	})
 	g.Go(func() error {
 		var err error
-		summary, err = s.postgres.GetGuildBattleSummary(ctx, region, *player.GuildName)
+		summary, err = s.postgres.GetGuildBattleSummary(ctx, region, *player.GuildName, since)
 		return err
 	})
 	g.Go(func() error {
@@ -64,6 +86,11 @@ func (s *Server) guildOverview(c *gin.Context) {
 		players, err = s.postgres.GetGuildPlayerStats(region, *player.GuildName)
 		return err
 	})
+	g.Go(func() error {
+		var err error
+		weapons, err = s.postgres.GetGuildRecentWeapons(ctx, region, *player.GuildName, compPlayerCount, compBattles)
+		return err
+	})
 
 	if err := g.Wait(); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get guild overview"})
@@ -75,5 +102,6 @@ func (s *Server) guildOverview(c *gin.Context) {
 		RosterStats:   roster,
 		BattleSummary: summary,
 		Players:       players,
+		Composition:   s.averageComposition(weapons),
 	})
 }
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"albionstats/internal/items"
+	"albionstats/internal/postgres"
+	"albionstats/internal/util"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const maxSoulSightings = 500
+
+type LegendarySoulResponse struct {
+	postgres.LegendarySoul
+	ItemInfo items.Info
+}
+
+type LegendarySoulHistoryResponse struct {
+	LegendarySoulResponse
+	Owners    []SoulOwner
+	Sightings []postgres.LegendarySoulSighting
+}
+
+// SoulOwner is a stretch of sightings where the soul was attuned to the same player.
+type SoulOwner struct {
+	PlayerID        *string
+	PlayerName      *string
+	FirstSeen       time.Time
+	LastSeen        time.Time
+	StartAttunement int64
+	EndAttunement   int64
+}
+
+func (s *Server) topLegendaries(c *gin.Context) {
+	region := c.Param("region")
+	if !util.IsValidServer(region) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid region"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter (must be 1-100)"})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset parameter"})
+		return
+	}
+
+	souls, err := s.postgres.GetTopLegendarySouls(c.Request.Context(), region, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get legendary weapons"})
+		return
+	}
+
+	resp := make([]LegendarySoulResponse, 0, len(souls))
+	for _, soul := range souls {
+		resp = append(resp, LegendarySoulResponse{
+			LegendarySoul: soul,
+			ItemInfo:      s.items.Lookup(soul.ItemType),
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// legendarySoul returns a soul with its owners and attunement over time.
+func (s *Server) legendarySoul(c *gin.Context) {
+	region := c.Param("region")
+	if !util.IsValidServer(region) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid region"})
+		return
+	}
+
+	soulID := c.Param("soulId")
+	soul, err := s.postgres.GetLegendarySoul(c.Request.Context(), region, soulID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Legendary soul not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get legendary soul"})
+		return
+	}
+
+	sightings, err := s.postgres.GetLegendarySoulSightings(c.Request.Context(), region, soulID, maxSoulSightings)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get legendary soul"})
+		return
+	}
+
+	c.JSON(http.StatusOK, LegendarySoulHistoryResponse{
+		LegendarySoulResponse: LegendarySoulResponse{
+			LegendarySoul: *soul,
+			ItemInfo:      s.items.Lookup(soul.ItemType),
+		},
+		Owners:    buildSoulOwners(sightings),
+		Sightings: sightings,
+	})
+}
+
+// buildSoulOwners walks sightings, newest first, and returns owners oldest first.
+func buildSoulOwners(sightings []postgres.LegendarySoulSighting) []SoulOwner {
+	owners := make([]SoulOwner, 0)
+	for i := len(sightings) - 1; i >= 0; i-- {
+		sighting := sightings[i]
+		if n := len(owners); n > 0 && sameOwner(owners[n-1].PlayerID, sighting.AttunedPlayerID) {
+			owners[n-1].LastSeen = sighting.TS
+			owners[n-1].EndAttunement = sighting.Attunement
+			continue
+		}
+		owners = append(owners, SoulOwner{
+			PlayerID:        sighting.AttunedPlayerID,
+			PlayerName:      sighting.AttunedPlayerName,
+			FirstSeen:       sighting.TS,
+			LastSeen:        sighting.TS,
+			StartAttunement: sighting.Attunement,
+			EndAttunement:   sighting.Attunement,
+		})
+	}
+	return owners
+}
+
+func sameOwner(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"albionstats/internal/items"
+	"albionstats/internal/postgres"
+	"albionstats/internal/util"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LoadoutResponse struct {
+	PlayerName string
+	BattleID   int64
+	Items      map[string]LoadoutItem
+}
+
+// KillLoadoutResponse is what the killer and victim wore in one kill.
+type KillLoadoutResponse struct {
+	EventID     int64
+	BattleID    int64
+	KillerName  string
+	KillerBuild map[string]LoadoutItem
+	VictimName  string
+	VictimBuild map[string]LoadoutItem
+}
+
+type LoadoutItem struct {
+	items.Info
+	Quality int16
+	Count   int32
+}
+
+// battleLoadouts lists the loadout each player was last seen with, per battle.
+func (s *Server) battleLoadouts(c *gin.Context) {
+	region := c.Param("region")
+	if !util.IsValidServer(region) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid region"})
+		return
+	}
+
+	battleIDs, ok := parseBattleIDs(c)
+	if !ok {
+		return
+	}
+
+	loadoutItems, err := s.postgres.GetBattleLoadoutsByIDs(c.Request.Context(), region, battleIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch loadouts"})
+		return
+	}
+	if len(loadoutItems) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No loadouts found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, s.groupBattleLoadouts(loadoutItems))
+}
+
+// groupBattleLoadouts folds slot rows, ordered by player and battle, into one
+// loadout per player per battle.
+func (s *Server) groupBattleLoadouts(loadoutItems []postgres.BattleLoadoutItem) []*LoadoutResponse {
+	loadouts := make([]*LoadoutResponse, 0)
+	var current *LoadoutResponse
+	for _, item := range loadoutItems {
+		if current == nil || current.PlayerName != item.PlayerName || current.BattleID != item.BattleID {
+			current = &LoadoutResponse{
+				PlayerName: item.PlayerName,
+				BattleID:   item.BattleID,
+				Items:      make(map[string]LoadoutItem),
+			}
+			loadouts = append(loadouts, current)
+		}
+		current.Items[item.Slot] = LoadoutItem{
+			Info:    s.items.Lookup(item.ItemType),
+			Quality: item.Quality,
+			Count:   item.Count,
+		}
+	}
+	return loadouts
+}
+
+// killLoadouts returns the killer's and victim's loadouts for a single kill.
+func (s *Server) killLoadouts(c *gin.Context) {
+	region := c.Param("region")
+	if !util.IsValidServer(region) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid region"})
+		return
+	}
+
+	eventID, err := strconv.ParseInt(c.Param("eventId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid eventId"})
+		return
+	}
+
+	loadoutItems, err := s.postgres.GetKillLoadoutsByEventIDs(c.Request.Context(), region, []int64{eventID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch loadouts"})
+		return
+	}
+	if len(loadoutItems) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No loadouts found"})
+		return
+	}
+
+	resp := KillLoadoutResponse{
+		EventID:     eventID,
+		BattleID:    loadoutItems[0].BattleID,
+		KillerBuild: make(map[string]LoadoutItem),
+		VictimBuild: make(map[string]LoadoutItem),
+	}
+	for _, item := range loadoutItems {
+		build := resp.KillerBuild
+		if item.Role == postgres.LoadoutRoleVictim {
+			build = resp.VictimBuild
+			resp.VictimName = item.PlayerName
+		} else {
+			resp.KillerName = item.PlayerName
+		}
+		build[item.Slot] = LoadoutItem{
+			Info:    s.items.Lookup(item.ItemType),
+			Quality: item.Quality,
+			Count:   item.Count,
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"albionstats/internal/postgres"
+	"albionstats/internal/util"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/sync/errgroup"
+)
+
+type KillMatrixResponse struct {
+	Region    string
+	BattleIDs []int64
+	Alliances []postgres.KillMatrixEntry
+	Guilds    []postgres.KillMatrixEntry
+}
+
+// battleKillMatrix reports who killed whom, by alliance and by guild.
+func (s *Server) battleKillMatrix(c *gin.Context) {
+	region := c.Param("region")
+	if !util.IsValidServer(region) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid region"})
+		return
+	}
+
+	battleIDs, ok := parseBattleIDs(c)
+	if !ok {
+		return
+	}
+
+	resp := KillMatrixResponse{
+		Region:    region,
+		BattleIDs: battleIDs,
+	}
+
+	g, ctx := errgroup.WithContext(c.Request.Context())
+	g.Go(func() error {
+		var err error
+		resp.Alliances, err = s.postgres.GetBattleAllianceKillMatrix(ctx, region, battleIDs)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		resp.Guilds, err = s.postgres.GetBattleGuildKillMatrix(ctx, region, battleIDs)
+		return err
+	})
+
+	if err := g.Wait(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch kill matrix"})
+		return
+	}
+
+	if len(resp.Guilds) == 0 && len(resp.Alliances) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No kills found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
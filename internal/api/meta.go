@@ -0,0 +1,147 @@
+package api
+
+import (
+	"albionstats/internal/items"
+	"albionstats/internal/postgres"
+	"albionstats/internal/util"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// battleSizeBuckets bound battle_summary.total_players for the size filter. A zero
+// maximum means no upper bound.
+var battleSizeBuckets = map[string]struct{ min, max int }{
+	"small":  {1, 19},
+	"medium": {20, 49},
+	"large":  {50, 99},
+	"zerg":   {100, 0},
+}
+
+type WeaponMetaStat struct {
+	Weapon      string
+	DisplayName string
+	Category    string
+	Usage       int64
+	Kills       int64
+	Deaths      int64
+	KD          float64
+	AvgDamage   int64
+	AvgHeal     int64
+	AvgIP       int64
+}
+
+// weaponMeta reports how each weapon performed in battles. Weapons are grouped by
+// family across tiers and enchantments unless groupBy=item.
+func (s *Server) weaponMeta(c *gin.Context) {
+	region := c.Param("region")
+	if !util.IsValidServer(region) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid region"})
+		return
+	}
+
+	since, ok := parseRecordWindow(c)
+	if !ok {
+		return
+	}
+
+	filter := postgres.WeaponMetaFilter{Since: since}
+
+	if size := c.Query("size"); size != "" {
+		bucket, ok := battleSizeBuckets[size]
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid size parameter (must be small, medium, large or zerg)"})
+			return
+		}
+		filter.MinPlayers = bucket.min
+		filter.MaxPlayers = bucket.max
+	}
+
+	var err error
+	filter.MinIP, err = strconv.Atoi(c.DefaultQuery("minIP", "0"))
+	if err != nil || filter.MinIP < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid minIP parameter"})
+		return
+	}
+
+	filter.MaxIP, err = strconv.Atoi(c.DefaultQuery("maxIP", "0"))
+	if err != nil || filter.MaxIP < 0 || (filter.MaxIP > 0 && filter.MaxIP < filter.MinIP) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid maxIP parameter"})
+		return
+	}
+
+	groupBy := c.DefaultQuery("groupBy", "family")
+	if groupBy != "family" && groupBy != "item" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid groupBy parameter (must be family or item)"})
+		return
+	}
+
+	rows, err := s.postgres.GetWeaponMeta(c.Request.Context(), region, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get weapon meta"})
+		return
+	}
+
+	c.JSON(http.StatusOK, s.buildWeaponMeta(rows, groupBy == "family"))
+}
+
+func (s *Server) buildWeaponMeta(rows []postgres.WeaponMetaRow, byFamily bool) []*WeaponMetaStat {
+	totals := make(map[string]*postgres.WeaponMetaRow)
+	for _, row := range rows {
+		key := row.Weapon
+		if byFamily {
+			key = items.Parse(row.Weapon).Family
+		}
+		t, ok := totals[key]
+		if !ok {
+			t = &postgres.WeaponMetaRow{Weapon: key}
+			totals[key] = t
+		}
+		t.Usage += row.Usage
+		t.Kills += row.Kills
+		t.Deaths += row.Deaths
+		t.Damage += row.Damage
+		t.DamageCount += row.DamageCount
+		t.Heal += row.Heal
+		t.HealCount += row.HealCount
+		t.IP += row.IP
+		t.IPCount += row.IPCount
+	}
+
+	stats := make([]*WeaponMetaStat, 0, len(totals))
+	for key, t := range totals {
+		info := s.items.Lookup(key)
+		stat := &WeaponMetaStat{
+			Weapon:      key,
+			DisplayName: info.DisplayName,
+			Category:    info.Category,
+			Usage:       t.Usage,
+			Kills:       t.Kills,
+			Deaths:      t.Deaths,
+			KD:          float64(t.Kills),
+		}
+		if t.Deaths > 0 {
+			stat.KD = float64(t.Kills) / float64(t.Deaths)
+		}
+		if t.DamageCount > 0 {
+			stat.AvgDamage = t.Damage / t.DamageCount
+		}
+		if t.HealCount > 0 {
+			stat.AvgHeal = t.Heal / t.HealCount
+		}
+		if t.IPCount > 0 {
+			stat.AvgIP = t.IP / t.IPCount
+		}
+		stats = append(stats, stat)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Usage != stats[j].Usage {
+			return stats[i].Usage > stats[j].Usage
+		}
+		return stats[i].Weapon < stats[j].Weapon
+	})
+	return stats
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"albionstats/internal/util"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// mvpCounts ranks players by how often they were picked as battle MVP, with their
+// side MVP and top healer, damage and tank counts.
+func (s *Server) mvpCounts(c *gin.Context) {
+	region := c.Param("region")
+	if !util.IsValidServer(region) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid region"})
+		return
+	}
+
+	since, ok := parseRecordWindow(c)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter (must be 1-100)"})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset parameter"})
+		return
+	}
+
+	counts, err := s.postgres.GetPlayerAwardCounts(c.Request.Context(), region, strings.TrimSpace(c.Query("player")), since, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get MVP counts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, counts)
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"albionstats/internal/postgres"
+	"albionstats/internal/util"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultRecordWindow = "30d"
+
+// recordWindows are the periods battle records and win rates can be viewed over.
+var recordWindows = map[string]time.Duration{
+	"7d":   7 * 24 * time.Hour,
+	"30d":  30 * 24 * time.Hour,
+	"90d":  90 * 24 * time.Hour,
+	"365d": 365 * 24 * time.Hour,
+}
+
+// parseRecordWindow reads the window query parameter as the start of the period,
+// writing a 400 response and returning false if it is invalid.
+func parseRecordWindow(c *gin.Context) (time.Time, bool) {
+	window, ok := recordWindows[c.DefaultQuery("window", defaultRecordWindow)]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid window parameter (must be 7d, 30d, 90d or 365d)"})
+		return time.Time{}, false
+	}
+	return time.Now().Add(-window), true
+}
+
+func (s *Server) allianceWinRates(c *gin.Context) {
+	s.winRates(c, s.postgres.GetAllianceWinRates)
+}
+
+func (s *Server) guildWinRates(c *gin.Context) {
+	s.winRates(c, s.postgres.GetGuildWinRates)
+}
+
+func (s *Server) winRates(c *gin.Context, fetch func(region string, since time.Time, minBattles, limit, offset int) ([]postgres.WinRateStats, error)) {
+	region := c.Param("region")
+	if !util.IsValidServer(region) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid region"})
+		return
+	}
+
+	since, ok := parseRecordWindow(c)
+	if !ok {
+		return
+	}
+
+	minBattles, err := strconv.Atoi(c.DefaultQuery("minBattles", "10"))
+	if err != nil || minBattles < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid minBattles parameter"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter (must be 1-100)"})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset parameter"})
+		return
+	}
+
+	stats, err := fetch(region, since, minBattles, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get win rates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
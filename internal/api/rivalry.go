@@ -0,0 +1,247 @@
+package api
+
+import (
+	"albionstats/internal/postgres"
+	"albionstats/internal/util"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RivalryResponse struct {
+	Region  string
+	Type    string
+	A       string
+	B       string
+	Totals  RivalryTotals
+	Battles []postgres.RivalryBattle
+	// Matrix breaks the kills between A and B down by guild for alliances, and by
+	// player for guilds.
+	Matrix []postgres.KillMatrixEntry
+	Trend  RivalryTrend
+}
+
+// RivalryTotals counts what A and B took from each other. Wins only count battles
+// where they were on opposite sides.
+type RivalryTotals struct {
+	Battles int
+	AKills  int64
+	BKills  int64
+	AFame   int64
+	BFame   int64
+	AWins   int
+	BWins   int
+	Draws   int
+}
+
+// RivalryTrend holds daily kills and fame exchanged, aligned with Timestamps.
+type RivalryTrend struct {
+	Timestamps []int64
+	AKills     []int32
+	BKills     []int32
+	AFame      []int64
+	BFame      []int64
+}
+
+func (s *Server) rivalry(c *gin.Context) {
+	region := c.Param("region")
+	if !util.IsValidServer(region) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid region"})
+		return
+	}
+
+	a := strings.TrimSpace(c.Query("a"))
+	b := strings.TrimSpace(c.Query("b"))
+	if a == "" || b == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Both a and b are required"})
+		return
+	}
+	if a == b {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a and b must be different"})
+		return
+	}
+
+	entityType := c.DefaultQuery("type", postgres.SideEntityAlliance)
+	if entityType != postgres.SideEntityAlliance && entityType != postgres.SideEntityGuild {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid type parameter (must be alliance or guild)"})
+		return
+	}
+
+	since, ok := parseRecordWindow(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	var (
+		battles []postgres.RivalryBattle
+		err     error
+	)
+	if entityType == postgres.SideEntityAlliance {
+		battles, err = s.postgres.GetAllianceRivalryBattles(ctx, region, a, b, since)
+	} else {
+		battles, err = s.postgres.GetGuildRivalryBattles(ctx, region, a, b, since)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch rivalry battles"})
+		return
+	}
+	if len(battles) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No battles found with both a and b"})
+		return
+	}
+
+	battleIDs := make([]int64, 0, len(battles))
+	for _, battle := range battles {
+		battleIDs = append(battleIDs, battle.BattleID)
+	}
+
+	var kills []postgres.BattleKills
+	if entityType == postgres.SideEntityAlliance {
+		kills, err = s.postgres.GetAllianceRivalryKills(ctx, region, battleIDs, a, b)
+	} else {
+		kills, err = s.postgres.GetGuildRivalryKills(ctx, region, battleIDs, a, b)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch rivalry kills"})
+		return
+	}
+
+	resp := RivalryResponse{
+		Region:  region,
+		Type:    entityType,
+		A:       a,
+		B:       b,
+		Totals:  buildRivalryTotals(battles),
+		Battles: battles,
+	}
+
+	// Kills are already limited to A and B, so the killer's side tells them apart
+	killedByA := func(kill postgres.BattleKills) bool {
+		if entityType == postgres.SideEntityAlliance {
+			return kill.KillerAlliance != nil && *kill.KillerAlliance == a
+		}
+		return kill.KillerGuild != nil && *kill.KillerGuild == a
+	}
+	for _, kill := range kills {
+		if killedByA(kill) {
+			resp.Totals.AKills++
+			resp.Totals.AFame += kill.Fame
+		} else {
+			resp.Totals.BKills++
+			resp.Totals.BFame += kill.Fame
+		}
+	}
+	resp.Matrix = buildRivalryMatrix(entityType, kills)
+	resp.Trend = buildRivalryTrend(kills, killedByA)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func buildRivalryTotals(battles []postgres.RivalryBattle) RivalryTotals {
+	totals := RivalryTotals{Battles: len(battles)}
+	for _, battle := range battles {
+		if battle.ASide == nil || battle.BSide == nil || *battle.ASide == *battle.BSide || battle.WinningSide == nil {
+			continue
+		}
+		switch *battle.WinningSide {
+		case 0:
+			totals.Draws++
+		case *battle.ASide:
+			totals.AWins++
+		default:
+			totals.BWins++
+		}
+	}
+	return totals
+}
+
+func buildRivalryMatrix(entityType string, kills []postgres.BattleKills) []postgres.KillMatrixEntry {
+	type pair struct{ killer, victim string }
+	entries := make(map[pair]*postgres.KillMatrixEntry)
+	for _, kill := range kills {
+		key := pair{kill.KillerName, kill.VictimName}
+		if entityType == postgres.SideEntityAlliance {
+			key = pair{derefString(kill.KillerGuild), derefString(kill.VictimGuild)}
+		}
+		entry, ok := entries[key]
+		if !ok {
+			entry = &postgres.KillMatrixEntry{Killer: key.killer, Victim: key.victim}
+			entries[key] = entry
+		}
+		entry.Kills++
+		entry.Fame += kill.Fame
+	}
+
+	matrix := make([]postgres.KillMatrixEntry, 0, len(entries))
+	for _, entry := range entries {
+		matrix = append(matrix, *entry)
+	}
+	sort.Slice(matrix, func(i, j int) bool {
+		if matrix[i].Fame != matrix[j].Fame {
+			return matrix[i].Fame > matrix[j].Fame
+		}
+		return matrix[i].Kills > matrix[j].Kills
+	})
+	return matrix
+}
+
+// buildRivalryTrend buckets kills per day from the first kill to the last, with empty
+// days included so every series has the same length.
+func buildRivalryTrend(kills []postgres.BattleKills, killedByA func(postgres.BattleKills) bool) RivalryTrend {
+	trend := RivalryTrend{
+		Timestamps: []int64{},
+		AKills:     []int32{},
+		BKills:     []int32{},
+		AFame:      []int64{},
+		BFame:      []int64{},
+	}
+	if len(kills) == 0 {
+		return trend
+	}
+
+	day := func(t time.Time) time.Time {
+		t = t.UTC()
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	first, last := day(kills[0].TS), day(kills[0].TS)
+	for _, kill := range kills {
+		if d := day(kill.TS); d.Before(first) {
+			first = d
+		} else if d.After(last) {
+			last = d
+		}
+	}
+
+	buckets := int(last.Sub(first)/(24*time.Hour)) + 1
+	trend.Timestamps = make([]int64, buckets)
+	trend.AKills = make([]int32, buckets)
+	trend.BKills = make([]int32, buckets)
+	trend.AFame = make([]int64, buckets)
+	trend.BFame = make([]int64, buckets)
+	for i := range trend.Timestamps {
+		trend.Timestamps[i] = first.AddDate(0, 0, i).UnixMilli()
+	}
+
+	for _, kill := range kills {
+		i := int(day(kill.TS).Sub(first) / (24 * time.Hour))
+		if killedByA(kill) {
+			trend.AKills[i]++
+			trend.AFame[i] += kill.Fame
+		} else {
+			trend.BKills[i]++
+			trend.BFame[i] += kill.Fame
+		}
+	}
+	return trend
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
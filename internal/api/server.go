@@ -1,7 +1,10 @@
 package api
 
 import (
+	"albionstats/internal/items"
 	"albionstats/internal/postgres"
+	"albionstats/internal/tasks"
+	"albionstats/internal/util"
 	"log/slog"
 	"strconv"
 	"strings"
@@ -13,15 +16,28 @@ import (
 )
 
 type Server struct {
-	postgres *postgres.Postgres
-	router   *gin.Engine
-	topCache *topCache
-	logger   *slog.Logger
+	postgres  *postgres.Postgres
+	apiClient *tasks.Client
+	router    *gin.Engine
+	topCache  *topCache
+	logger    *slog.Logger
+	items     *items.Catalogue
+	prices    *items.PriceTable
+	mvp       util.MVPWeights
+	deaths    *deathsCache
 }
 
 type Config struct {
 	Postgres *postgres.Postgres
-	Logger   *slog.Logger
+	// APIClient fetches live data from the game API, e.g. player deaths.
+	APIClient *tasks.Client
+	Logger    *slog.Logger
+	// Items enriches item ids in responses. Nil is allowed.
+	Items *items.Catalogue
+	// Prices values equipment in death recaps. Nil is allowed.
+	Prices *items.PriceTable
+	// MVPWeights scores players in battle reports, matching the stored awards.
+	MVPWeights util.MVPWeights
 }
 
 func NewServer(cfg Config) *Server {
@@ -32,10 +48,15 @@ func NewServer(cfg Config) *Server {
 	router.Use(corsMiddleware())
 
 	server := &Server{
-		postgres: cfg.Postgres,
-		router:   router,
-		topCache: newTopCache(),
-		logger:   cfg.Logger,
+		postgres:  cfg.Postgres,
+		apiClient: cfg.APIClient,
+		router:    router,
+		topCache:  newTopCache(),
+		logger:    cfg.Logger,
+		items:     cfg.Items,
+		prices:    cfg.Prices,
+		mvp:       cfg.MVPWeights,
+		deaths:    newDeathsCache(),
 	}
 
 	server.setupRoutes()
@@ -78,6 +99,7 @@ func (s *Server) setupRoutes() {
 	v1.GET("/metrics/dau", s.metricsDAU)
 	v1.GET("/metrics/:metricId", s.metrics)
 	v1.GET("/players/:server/:name", s.player)
+	v1.GET("/players/:server/:name/deaths", s.playerDeaths)
 	v1.GET("/guilds/:server/:name", s.guildOverview)
 	v1.GET("/players/search/:server/:query", s.searchPlayers)
 	v1.GET("/guilds/search/:server/:query", s.searchGuilds)
@@ -86,11 +108,27 @@ func (s *Server) setupRoutes() {
 	v1.GET("/alliances/top/:region", s.topAlliances)
 	v1.GET("/guilds/top/:region", s.topGuilds)
 	v1.GET("/players/top/:region", s.topPlayers)
+	v1.GET("/players/mvp/:region", s.mvpCounts)
+	v1.GET("/alliances/winrate/:region", s.allianceWinRates)
+	v1.GET("/alliances/graph/:region", s.allianceGraph)
+	v1.GET("/guilds/winrate/:region", s.guildWinRates)
 	v1.GET("/boards/:region", s.battleSummaries)
 	v1.GET("/boards/guild/:region/:guildName", s.battleGuildSummaries)
 	v1.GET("/boards/alliance/:region/:allianceName", s.battleAllianceSummaries)
 	v1.GET("/boards/player/:region/:playerName", s.battlePlayerSummaries)
+	v1.GET("/battles/:region", s.battlesInWindow)
 	v1.GET("/battles/:region/:battleId", s.battle)
+	v1.GET("/battles/:region/:battleId/timeline", s.battleTimeline)
+	v1.GET("/battles/:region/:battleId/matrix", s.battleKillMatrix)
+	v1.GET("/battles/:region/:battleId/loadouts", s.battleLoadouts)
+	v1.GET("/kills/:region/:eventId/loadouts", s.killLoadouts)
+	v1.GET("/fights/:region/:battleId", s.fight)
+	v1.GET("/rivalry/:region", s.rivalry)
+	v1.GET("/meta/:region", s.weaponMeta)
+	v1.GET("/legendaries/top/:region", s.topLegendaries)
+	v1.GET("/smallscale/top/:region", s.topSmallScale)
+	v1.GET("/smallscale/:region/:playerName", s.playerSmallScale)
+	v1.GET("/legendaries/:region/:soulId", s.legendarySoul)
 }
 
 func (s *Server) Run(addr string) error {
@@ -108,6 +146,7 @@ func corsMiddleware() gin.HandlerFunc {
 		c.Header("Access-Control-Allow-Credentials", "true")
 		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
 		c.Header("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+		c.Header("Access-Control-Expose-Headers", nextCursorHeader)
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
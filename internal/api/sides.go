@@ -0,0 +1,177 @@
+package api
+
+import (
+	"albionstats/internal/postgres"
+	"albionstats/internal/util"
+	"sort"
+)
+
+type MergedSideStat struct {
+	Side        int16
+	Alliances   []string
+	Guilds      []string
+	PlayerCount int32
+	Kills       int32
+	Deaths      int32
+	KillFame    int64
+	DeathFame   int64
+	SilverLost  int64
+	Composition RoleComposition
+}
+
+// battleSides holds the side of each alliance and guild across a merged report.
+type battleSides struct {
+	alliances map[string]int16
+	guilds    map[string]int16
+}
+
+// sideOf returns the side a player fought on, by alliance then guild.
+func (b battleSides) sideOf(allianceName, guildName *string) (int16, bool) {
+	if allianceName != nil {
+		if side, ok := b.alliances[*allianceName]; ok {
+			return side, true
+		}
+	}
+	if guildName != nil {
+		if side, ok := b.guilds[*guildName]; ok {
+			return side, true
+		}
+	}
+	return 0, false
+}
+
+// mergeBattleSides combines the sides inferred for each battle. Side numbers are only
+// meaningful within one battle, so each battle is flipped where needed to agree with
+// the battle that has the most assignments before the sides are merged.
+func mergeBattleSides(assignments []postgres.BattleSideAssignment) battleSides {
+	byBattle := make(map[int64][]postgres.BattleSideAssignment)
+	for _, a := range assignments {
+		byBattle[a.BattleID] = append(byBattle[a.BattleID], a)
+	}
+
+	battleIDs := make([]int64, 0, len(byBattle))
+	for id := range byBattle {
+		battleIDs = append(battleIDs, id)
+	}
+	sort.Slice(battleIDs, func(i, j int) bool {
+		if len(byBattle[battleIDs[i]]) != len(byBattle[battleIDs[j]]) {
+			return len(byBattle[battleIDs[i]]) > len(byBattle[battleIDs[j]])
+		}
+		return battleIDs[i] < battleIDs[j]
+	})
+
+	key := func(a postgres.BattleSideAssignment) string {
+		return a.EntityType + ":" + a.EntityName
+	}
+
+	votes := make(map[string]*[3]int)
+	for _, id := range battleIDs {
+		agreement := 0
+		for _, a := range byBattle[id] {
+			if v, ok := votes[key(a)]; ok {
+				agreement += v[a.Side] - v[3-a.Side]
+			}
+		}
+		flip := agreement < 0
+
+		for _, a := range byBattle[id] {
+			side := a.Side
+			if flip {
+				side = 3 - side
+			}
+			v, ok := votes[key(a)]
+			if !ok {
+				v = &[3]int{}
+				votes[key(a)] = v
+			}
+			v[side]++
+		}
+	}
+
+	sides := battleSides{
+		alliances: make(map[string]int16),
+		guilds:    make(map[string]int16),
+	}
+	for _, id := range battleIDs {
+		for _, a := range byBattle[id] {
+			v := votes[key(a)]
+			side := int16(1)
+			if v[2] > v[1] {
+				side = 2
+			}
+			if a.EntityType == postgres.SideEntityAlliance {
+				sides.alliances[a.EntityName] = side
+			} else {
+				sides.guilds[a.EntityName] = side
+			}
+		}
+	}
+	return sides
+}
+
+// buildSideStats totals the players on each side. The winner is picked from kill fame
+// taken across sides, as the battle poller does for battle_summary.winning_side.
+func buildSideStats(sides battleSides, playerStats []postgres.BattlePlayerStats, kills []postgres.BattleKills, roles map[string]string) ([]*MergedSideStat, *int16) {
+	if len(sides.alliances) == 0 && len(sides.guilds) == 0 {
+		return nil, nil
+	}
+
+	stats := []*MergedSideStat{{Side: 1}, {Side: 2}}
+	players := [2]map[string]struct{}{{}, {}}
+	for _, stat := range playerStats {
+		side, ok := sides.sideOf(stat.AllianceName, stat.GuildName)
+		if !ok {
+			continue
+		}
+		m := stats[side-1]
+		if _, ok := players[side-1][stat.PlayerName]; !ok {
+			players[side-1][stat.PlayerName] = struct{}{}
+			m.Composition.add(roles[stat.PlayerName])
+		}
+		m.Kills += stat.Kills
+		m.Deaths += stat.Deaths
+		m.KillFame += stat.KillFame
+		if stat.DeathFame != nil {
+			m.DeathFame += *stat.DeathFame
+		}
+		if stat.SilverLost != nil {
+			m.SilverLost += *stat.SilverLost
+		}
+	}
+
+	for i, m := range stats {
+		m.PlayerCount = int32(len(players[i]))
+		m.Alliances = []string{}
+		m.Guilds = []string{}
+	}
+	for name, side := range sides.alliances {
+		stats[side-1].Alliances = append(stats[side-1].Alliances, name)
+	}
+	for name, side := range sides.guilds {
+		stats[side-1].Guilds = append(stats[side-1].Guilds, name)
+	}
+	for _, m := range stats {
+		sort.Strings(m.Alliances)
+		sort.Strings(m.Guilds)
+	}
+
+	return stats, crossSideWinner(sides, kills)
+}
+
+// crossSideWinner totals the kill fame each side took from the other. Kills by players
+// without a side and kills within a side don't count.
+func crossSideWinner(sides battleSides, kills []postgres.BattleKills) *int16 {
+	var fame [3]int64
+	for _, kill := range kills {
+		killerSide, ok := sides.sideOf(kill.KillerAlliance, kill.KillerGuild)
+		if !ok {
+			continue
+		}
+		victimSide, _ := sides.sideOf(kill.VictimAlliance, kill.VictimGuild)
+		if killerSide == victimSide {
+			continue
+		}
+		fame[killerSide] += kill.Fame
+	}
+	return util.WinningSide(fame[1], fame[2])
+}
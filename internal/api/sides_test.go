@@ -0,0 +1,62 @@
+package api
+
+import (
+	"albionstats/internal/postgres"
+	"testing"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestCrossSideWinner(t *testing.T) {
+	sides := battleSides{
+		alliances: map[string]int16{"A": 1, "B": 2},
+		guilds:    map[string]int16{"Loose": 2},
+	}
+	kill := func(killerAlliance, killerGuild, victimAlliance *string, fame int64) postgres.BattleKills {
+		return postgres.BattleKills{
+			KillerAlliance: killerAlliance,
+			KillerGuild:    killerGuild,
+			VictimAlliance: victimAlliance,
+			Fame:           fame,
+		}
+	}
+
+	tests := []struct {
+		name  string
+		kills []postgres.BattleKills
+		want  *int16
+	}{
+		{name: "no kills", want: nil},
+		{
+			name:  "side one takes more fame",
+			kills: []postgres.BattleKills{kill(strPtr("A"), nil, strPtr("B"), 300), kill(strPtr("B"), nil, strPtr("A"), 100)},
+			want:  sidePtr(1),
+		},
+		{
+			// Summed player fame would pick side 1 here
+			name:  "kills within a side don't count",
+			kills: []postgres.BattleKills{kill(strPtr("A"), nil, strPtr("A"), 1000), kill(strPtr("B"), nil, strPtr("A"), 100)},
+			want:  sidePtr(2),
+		},
+		{
+			name:  "guild without an alliance uses its guild side",
+			kills: []postgres.BattleKills{kill(nil, strPtr("Loose"), strPtr("A"), 100)},
+			want:  sidePtr(2),
+		},
+		{
+			name:  "killers without a side don't count",
+			kills: []postgres.BattleKills{kill(strPtr("Z"), nil, strPtr("A"), 1000), kill(strPtr("A"), nil, strPtr("B"), 100)},
+			want:  sidePtr(1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := crossSideWinner(sides, tt.kills)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("crossSideWinner() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func sidePtr(s int16) *int16 { return &s }
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"albionstats/internal/postgres"
+	"albionstats/internal/util"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SmallScaleResponse struct {
+	postgres.SmallScaleStats
+	// GankRatio is the share of kills made with three or more players credited, and
+	// GankedRatio the share of deaths to three or more.
+	GankRatio   float64
+	GankedRatio float64
+	SoloKD      float64
+	SmallKD     float64
+}
+
+func newSmallScaleResponse(stats postgres.SmallScaleStats) SmallScaleResponse {
+	resp := SmallScaleResponse{
+		SmallScaleStats: stats,
+		SoloKD:          ratio(stats.SoloKills, stats.SoloDeaths),
+		SmallKD:         ratio(stats.SmallKills, stats.SmallDeaths),
+	}
+	if stats.Kills > 0 {
+		resp.GankRatio = float64(stats.GankKills) / float64(stats.Kills)
+	}
+	if stats.Deaths > 0 {
+		resp.GankedRatio = float64(stats.GankedDeaths) / float64(stats.Deaths)
+	}
+	return resp
+}
+
+// ratio is kills over deaths, or kills when there are no deaths.
+func ratio(kills, deaths int64) float64 {
+	if deaths == 0 {
+		return float64(kills)
+	}
+	return float64(kills) / float64(deaths)
+}
+
+// playerSmallScale reports a player's open world kills and deaths by group size.
+func (s *Server) playerSmallScale(c *gin.Context) {
+	region := c.Param("region")
+	if !util.IsValidServer(region) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid region"})
+		return
+	}
+
+	since, ok := parseRecordWindow(c)
+	if !ok {
+		return
+	}
+
+	stats, err := s.postgres.GetPlayerSmallScaleStats(c.Request.Context(), region, c.Param("playerName"), since)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get small-scale stats"})
+		return
+	}
+
+	c.JSON(http.StatusOK, newSmallScaleResponse(*stats))
+}
+
+func (s *Server) topSmallScale(c *gin.Context) {
+	region := c.Param("region")
+	if !util.IsValidServer(region) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid region"})
+		return
+	}
+
+	since, ok := parseRecordWindow(c)
+	if !ok {
+		return
+	}
+
+	bracket := c.DefaultQuery("bracket", postgres.SmallScaleSolo)
+	if bracket != postgres.SmallScaleSolo && bracket != postgres.SmallScaleSmall {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bracket parameter (must be solo or small)"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter (must be 1-100)"})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset parameter"})
+		return
+	}
+
+	stats, err := s.postgres.GetTopSmallScalePlayers(c.Request.Context(), region, bracket, since, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get small-scale leaderboard"})
+		return
+	}
+
+	resp := make([]SmallScaleResponse, 0, len(stats))
+	for _, stat := range stats {
+		resp = append(resp, newSmallScaleResponse(stat))
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"albionstats/internal/postgres"
+	"albionstats/internal/util"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errTimelineTooLong is returned when the kills span more than maxBattleWindow, which
+// would mean allocating a bucket for every minute in between.
+var errTimelineTooLong = errors.New("battle timeline spans too long")
+
+type BattleTimelineResponse struct {
+	Region     string
+	BattleIDs  []int64
+	Timestamps []int64
+	Alliances  []*TimelineSeries
+	Guilds     []*TimelineSeries
+}
+
+// TimelineSeries holds per-minute values for one alliance or guild, aligned with
+// BattleTimelineResponse.Timestamps.
+type TimelineSeries struct {
+	Name                string
+	Kills               []int32
+	Deaths              []int32
+	KillFame            []int64
+	DeathFame           []int64
+	CumulativeKillFame  []int64
+	CumulativeDeathFame []int64
+}
+
+func (s *Server) battleTimeline(c *gin.Context) {
+	region := c.Param("region")
+	if !util.IsValidServer(region) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid region"})
+		return
+	}
+
+	battleIDs, ok := parseBattleIDs(c)
+	if !ok {
+		return
+	}
+
+	kills, err := s.postgres.GetBattleKillsByIDs(c.Request.Context(), region, battleIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch battle kills"})
+		return
+	}
+	if len(kills) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No kills found"})
+		return
+	}
+
+	resp, err := buildBattleTimeline(region, battleIDs, kills)
+	if errors.Is(err, errTimelineTooLong) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Battles must span at most 24 hours"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// buildBattleTimeline buckets kills per minute from the first kill to the last, with
+// empty minutes included so every series has the same length.
+func buildBattleTimeline(region string, battleIDs []int64, kills []postgres.BattleKills) (BattleTimelineResponse, error) {
+	first, last := kills[0].TS, kills[0].TS
+	for _, kill := range kills {
+		if kill.TS.Before(first) {
+			first = kill.TS
+		}
+		if kill.TS.After(last) {
+			last = kill.TS
+		}
+	}
+	first = first.Truncate(time.Minute)
+	last = last.Truncate(time.Minute)
+	if last.Sub(first) > maxBattleWindow {
+		return BattleTimelineResponse{}, errTimelineTooLong
+	}
+
+	buckets := int(last.Sub(first)/time.Minute) + 1
+	resp := BattleTimelineResponse{
+		Region:     region,
+		BattleIDs:  battleIDs,
+		Timestamps: make([]int64, buckets),
+	}
+	for i := range resp.Timestamps {
+		resp.Timestamps[i] = first.Add(time.Duration(i) * time.Minute).UnixMilli()
+	}
+
+	alliances := make(map[string]*TimelineSeries)
+	guilds := make(map[string]*TimelineSeries)
+	for _, kill := range kills {
+		i := int(kill.TS.Truncate(time.Minute).Sub(first) / time.Minute)
+		if kill.KillerAlliance != nil {
+			timelineSeries(alliances, *kill.KillerAlliance, buckets).addKill(i, kill.Fame)
+		}
+		if kill.VictimAlliance != nil {
+			timelineSeries(alliances, *kill.VictimAlliance, buckets).addDeath(i, kill.Fame)
+		}
+		if kill.KillerGuild != nil {
+			timelineSeries(guilds, *kill.KillerGuild, buckets).addKill(i, kill.Fame)
+		}
+		if kill.VictimGuild != nil {
+			timelineSeries(guilds, *kill.VictimGuild, buckets).addDeath(i, kill.Fame)
+		}
+	}
+
+	resp.Alliances = finishTimelineSeries(alliances)
+	resp.Guilds = finishTimelineSeries(guilds)
+	return resp, nil
+}
+
+func timelineSeries(series map[string]*TimelineSeries, name string, buckets int) *TimelineSeries {
+	s, ok := series[name]
+	if !ok {
+		s = &TimelineSeries{
+			Name:      name,
+			Kills:     make([]int32, buckets),
+			Deaths:    make([]int32, buckets),
+			KillFame:  make([]int64, buckets),
+			DeathFame: make([]int64, buckets),
+		}
+		series[name] = s
+	}
+	return s
+}
+
+func (s *TimelineSeries) addKill(i int, fame int64) {
+	s.Kills[i]++
+	s.KillFame[i] += fame
+}
+
+func (s *TimelineSeries) addDeath(i int, fame int64) {
+	s.Deaths[i]++
+	s.DeathFame[i] += fame
+}
+
+// finishTimelineSeries fills in the cumulative lines and sorts by total kill fame.
+func finishTimelineSeries(series map[string]*TimelineSeries) []*TimelineSeries {
+	result := make([]*TimelineSeries, 0, len(series))
+	for _, s := range series {
+		s.CumulativeKillFame = make([]int64, len(s.KillFame))
+		s.CumulativeDeathFame = make([]int64, len(s.DeathFame))
+		var killFame, deathFame int64
+		for i := range s.KillFame {
+			killFame += s.KillFame[i]
+			deathFame += s.DeathFame[i]
+			s.CumulativeKillFame[i] = killFame
+			s.CumulativeDeathFame[i] = deathFame
+		}
+		result = append(result, s)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].CumulativeKillFame, result[j].CumulativeKillFame
+		return a[len(a)-1] > b[len(b)-1]
+	})
+	return result
+}
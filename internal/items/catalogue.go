@@ -0,0 +1,127 @@
+package items
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// tierPrefixes are the in-game name prefixes for tiers 1 to 8.
+var tierPrefixes = map[int]string{
+	1: "Beginner's",
+	2: "Novice's",
+	3: "Journeyman's",
+	4: "Adept's",
+	5: "Expert's",
+	6: "Master's",
+	7: "Grandmaster's",
+	8: "Elder's",
+}
+
+// Metadata describes an item family in the catalogue file.
+type Metadata struct {
+	DisplayName string   `json:"displayName"`
+	Category    string   `json:"category"`
+	Roles       []string `json:"roles"`
+}
+
+// Info is an item id enriched for API responses.
+type Info struct {
+	Type        string
+	Tier        int
+	Enchant     int
+	DisplayName string
+	Category    string
+}
+
+// Catalogue holds item metadata keyed by family. A nil Catalogue is valid and
+// knows no items.
+type Catalogue struct {
+	families map[string]Metadata
+}
+
+// Load reads a JSON object mapping item families (or full item ids, for items that
+// differ between tiers) to their metadata.
+func Load(path string) (*Catalogue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read items file: %w", err)
+	}
+
+	families := make(map[string]Metadata)
+	if err := json.Unmarshal(data, &families); err != nil {
+		return nil, fmt.Errorf("parse items file: %w", err)
+	}
+
+	return &Catalogue{families: families}, nil
+}
+
+// Metadata returns the catalogue entry for an item id, trying the exact id before
+// its family.
+func (c *Catalogue) Metadata(id string) (Metadata, bool) {
+	if c == nil {
+		return Metadata{}, false
+	}
+	if m, ok := c.families[id]; ok {
+		return m, true
+	}
+	m, ok := c.families[Parse(id).Family]
+	return m, ok
+}
+
+// Lookup enriches an item id. Items missing from the catalogue get a name built from
+// the family and their slot as category.
+func (c *Catalogue) Lookup(id string) Info {
+	item := Parse(id)
+	info := Info{
+		Type:    id,
+		Tier:    item.Tier,
+		Enchant: item.Enchant,
+	}
+
+	name := familyName(item.Family)
+	info.Category = strings.ToLower(item.Slot)
+	if m, ok := c.Metadata(id); ok {
+		if m.DisplayName != "" {
+			name = m.DisplayName
+		}
+		if m.Category != "" {
+			info.Category = m.Category
+		}
+	}
+
+	if prefix, ok := tierPrefixes[item.Tier]; ok {
+		name = prefix + " " + name
+	}
+	info.DisplayName = name
+	return info
+}
+
+// LookupPtr is Lookup for optional item ids, returning nil for nil or empty ids.
+func (c *Catalogue) LookupPtr(id *string) *Info {
+	if id == nil || *id == "" {
+		return nil
+	}
+	info := c.Lookup(*id)
+	return &info
+}
+
+// familyName turns a family like 2H_DUALAXE_KEEPER into "Dualaxe Keeper", dropping
+// the slot prefix.
+func familyName(family string) string {
+	for _, p := range slotPrefixes {
+		if strings.HasPrefix(family, p.prefix) && len(family) > len(p.prefix) {
+			family = family[len(p.prefix):]
+			break
+		}
+	}
+
+	words := strings.Split(strings.ToLower(family), "_")
+	for i, w := range words {
+		if w != "" {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
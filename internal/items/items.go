@@ -0,0 +1,82 @@
+// Package items parses Albion Online item ids such as T8_2H_CLAYMORE@3 and looks
+// them up in a local item catalogue.
+package items
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Equipment slots, matching the keys of an event's Equipment map.
+const (
+	SlotMainHand = "MainHand"
+	SlotOffHand  = "OffHand"
+	SlotHead     = "Head"
+	SlotArmor    = "Armor"
+	SlotShoes    = "Shoes"
+	SlotBag      = "Bag"
+	SlotCape     = "Cape"
+	SlotMount    = "Mount"
+	SlotPotion   = "Potion"
+	SlotFood     = "Food"
+)
+
+// slotPrefixes maps the start of an item family to its slot. Two-handed weapons take
+// the main hand slot.
+var slotPrefixes = []struct {
+	prefix string
+	slot   string
+}{
+	{"MAIN_", SlotMainHand},
+	{"2H_", SlotMainHand},
+	{"OFF_", SlotOffHand},
+	{"HEAD_", SlotHead},
+	{"ARMOR_", SlotArmor},
+	{"SHOES_", SlotShoes},
+	{"BAG", SlotBag},
+	{"CAPE", SlotCape},
+	{"MOUNT_", SlotMount},
+	{"POTION_", SlotPotion},
+	{"MEAL_", SlotFood},
+}
+
+var tierPattern = regexp.MustCompile(`^T(\d)_(.+)$`)
+
+// Item is what can be read from an item id alone.
+type Item struct {
+	ID      string
+	Tier    int
+	Enchant int
+	// Family is the id without tier and enchantment, shared by every tier of the
+	// item, e.g. 2H_CLAYMORE.
+	Family string
+	Slot   string
+}
+
+// Parse splits an item id into its parts. Ids without a tier, like UNIQUE_ items,
+// have Tier 0; unknown families have an empty Slot.
+func Parse(id string) Item {
+	item := Item{ID: id, Family: id}
+
+	if at := strings.LastIndexByte(item.Family, '@'); at >= 0 {
+		if enchant, err := strconv.Atoi(item.Family[at+1:]); err == nil {
+			item.Enchant = enchant
+			item.Family = item.Family[:at]
+		}
+	}
+
+	if m := tierPattern.FindStringSubmatch(item.Family); m != nil {
+		item.Tier, _ = strconv.Atoi(m[1])
+		item.Family = m[2]
+	}
+
+	for _, p := range slotPrefixes {
+		if strings.HasPrefix(item.Family, p.prefix) {
+			item.Slot = p.slot
+			break
+		}
+	}
+
+	return item
+}
@@ -0,0 +1,32 @@
+package items
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		id   string
+		want Item
+	}{
+		{"T8_2H_CLAYMORE@3", Item{ID: "T8_2H_CLAYMORE@3", Tier: 8, Enchant: 3, Family: "2H_CLAYMORE", Slot: SlotMainHand}},
+		{"T4_MAIN_SWORD", Item{ID: "T4_MAIN_SWORD", Tier: 4, Family: "MAIN_SWORD", Slot: SlotMainHand}},
+		{"T6_OFF_SHIELD@1", Item{ID: "T6_OFF_SHIELD@1", Tier: 6, Enchant: 1, Family: "OFF_SHIELD", Slot: SlotOffHand}},
+		{"T5_HEAD_PLATE_SET1", Item{ID: "T5_HEAD_PLATE_SET1", Tier: 5, Family: "HEAD_PLATE_SET1", Slot: SlotHead}},
+		{"T7_ARMOR_CLOTH_KEEPER@2", Item{ID: "T7_ARMOR_CLOTH_KEEPER@2", Tier: 7, Enchant: 2, Family: "ARMOR_CLOTH_KEEPER", Slot: SlotArmor}},
+		{"T4_BAG", Item{ID: "T4_BAG", Tier: 4, Family: "BAG", Slot: SlotBag}},
+		{"T4_CAPEITEM_FW_MARTLOCK", Item{ID: "T4_CAPEITEM_FW_MARTLOCK", Tier: 4, Family: "CAPEITEM_FW_MARTLOCK", Slot: SlotCape}},
+		{"T8_MOUNT_ARMORED_HORSE", Item{ID: "T8_MOUNT_ARMORED_HORSE", Tier: 8, Family: "MOUNT_ARMORED_HORSE", Slot: SlotMount}},
+		{"T6_POTION_HEAL", Item{ID: "T6_POTION_HEAL", Tier: 6, Family: "POTION_HEAL", Slot: SlotPotion}},
+		{"T7_MEAL_STEW@1", Item{ID: "T7_MEAL_STEW@1", Tier: 7, Enchant: 1, Family: "MEAL_STEW", Slot: SlotFood}},
+		{"UNIQUE_HIDEOUT", Item{ID: "UNIQUE_HIDEOUT", Family: "UNIQUE_HIDEOUT"}},
+		{"T4_UNKNOWN_THING", Item{ID: "T4_UNKNOWN_THING", Tier: 4, Family: "UNKNOWN_THING"}},
+		{"T4_MAIN_SWORD@x", Item{ID: "T4_MAIN_SWORD@x", Tier: 4, Family: "MAIN_SWORD@x", Slot: SlotMainHand}},
+		{"", Item{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if got := Parse(tt.id); got != tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
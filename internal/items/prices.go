@@ -0,0 +1,107 @@
+package items
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type priceKey struct {
+	id      string
+	quality int32
+}
+
+type price struct {
+	silver int64
+	at     time.Time
+}
+
+// PriceTable holds the latest known price of each item id and quality. A nil
+// PriceTable is valid and prices nothing.
+type PriceTable struct {
+	prices map[priceKey]price
+	// fallback is the cheapest quality of each id, used when the exact quality is
+	// missing.
+	fallback map[string]int64
+}
+
+// LoadPrices reads a CSV file with the header item_id,quality,price,timestamp, where
+// timestamp is RFC3339. When an item and quality appear more than once the newest
+// row wins.
+func LoadPrices(path string) (*PriceTable, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open prices file: %w", err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = 4
+	r.TrimLeadingSpace = true
+
+	if _, err := r.Read(); err != nil {
+		return nil, fmt.Errorf("read prices header: %w", err)
+	}
+
+	table := &PriceTable{
+		prices:   make(map[priceKey]price),
+		fallback: make(map[string]int64),
+	}
+	for line := 2; ; line++ {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read prices line %d: %w", line, err)
+		}
+
+		quality, err := strconv.ParseInt(strings.TrimSpace(record[1]), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("prices line %d: invalid quality: %w", line, err)
+		}
+		silver, err := strconv.ParseInt(strings.TrimSpace(record[2]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("prices line %d: invalid price: %w", line, err)
+		}
+		at, err := time.Parse(time.RFC3339, strings.TrimSpace(record[3]))
+		if err != nil {
+			return nil, fmt.Errorf("prices line %d: invalid timestamp: %w", line, err)
+		}
+
+		key := priceKey{id: strings.TrimSpace(record[0]), quality: int32(quality)}
+		if existing, ok := table.prices[key]; ok && existing.at.After(at) {
+			continue
+		}
+		table.prices[key] = price{silver: silver, at: at}
+	}
+
+	for key, p := range table.prices {
+		if cheapest, ok := table.fallback[key.id]; !ok || p.silver < cheapest {
+			table.fallback[key.id] = p.silver
+		}
+	}
+
+	return table, nil
+}
+
+// Value estimates the silver worth of count items of the given id and quality, or 0
+// if the item has no price.
+func (t *PriceTable) Value(id string, quality int32, count int32) int64 {
+	if t == nil || id == "" {
+		return 0
+	}
+	if count < 1 {
+		count = 1
+	}
+
+	if p, ok := t.prices[priceKey{id: id, quality: quality}]; ok {
+		return p.silver * int64(count)
+	}
+	return t.fallback[id] * int64(count)
+}
@@ -0,0 +1,86 @@
+package items
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writePrices(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "prices.csv")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPrices(t *testing.T) {
+	path := writePrices(t, `item_id,quality,price,timestamp
+T8_2H_CLAYMORE@3,1,900000,2025-01-01T00:00:00Z
+T8_2H_CLAYMORE@3,1,1000000,2025-01-02T00:00:00Z
+T8_2H_CLAYMORE@3,1,800000,2024-12-31T00:00:00Z
+T8_2H_CLAYMORE@3,4,1500000,2025-01-01T00:00:00Z
+ T6_POTION_HEAL , 1 , 5000 , 2025-01-01T00:00:00Z
+`)
+
+	table, err := LoadPrices(path)
+	if err != nil {
+		t.Fatalf("LoadPrices() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		id      string
+		quality int32
+		count   int32
+		want    int64
+	}{
+		{"newest row wins", "T8_2H_CLAYMORE@3", 1, 1, 1000000},
+		{"exact quality", "T8_2H_CLAYMORE@3", 4, 1, 1500000},
+		{"missing quality falls back to the cheapest", "T8_2H_CLAYMORE@3", 2, 1, 1000000},
+		{"count multiplies", "T6_POTION_HEAL", 1, 10, 50000},
+		{"zero count counts as one", "T6_POTION_HEAL", 1, 0, 5000},
+		{"fields are trimmed", "T6_POTION_HEAL", 1, 1, 5000},
+		{"unknown item", "T4_BAG", 1, 1, 0},
+		{"empty id", "", 1, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := table.Value(tt.id, tt.quality, tt.count); got != tt.want {
+				t.Errorf("Value(%q, %d, %d) = %d, want %d", tt.id, tt.quality, tt.count, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadPricesErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+	}{
+		{"invalid quality", "item_id,quality,price,timestamp\nT4_BAG,x,100,2025-01-01T00:00:00Z\n"},
+		{"invalid price", "item_id,quality,price,timestamp\nT4_BAG,1,lots,2025-01-01T00:00:00Z\n"},
+		{"invalid timestamp", "item_id,quality,price,timestamp\nT4_BAG,1,100,yesterday\n"},
+		{"wrong field count", "item_id,quality,price,timestamp\nT4_BAG,1,100\n"},
+		{"empty file", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadPrices(writePrices(t, tt.contents)); err == nil {
+				t.Error("LoadPrices() error = nil, want an error")
+			}
+		})
+	}
+
+	if _, err := LoadPrices(filepath.Join(t.TempDir(), "missing.csv")); err == nil {
+		t.Error("LoadPrices() of a missing file error = nil, want an error")
+	}
+}
+
+func TestNilPriceTable(t *testing.T) {
+	var table *PriceTable
+	if got := table.Value("T4_BAG", 1, 1); got != 0 {
+		t.Errorf("nil table Value() = %d, want 0", got)
+	}
+}
@@ -0,0 +1,77 @@
+package items
+
+import "strings"
+
+// Battle roles a weapon is played as.
+const (
+	RoleTank    = "tank"
+	RoleHealer  = "healer"
+	RoleSupport = "support"
+	RoleMelee   = "melee"
+	RoleRanged  = "ranged"
+)
+
+// Roles lists every role, in the order compositions are shown.
+var Roles = []string{RoleTank, RoleHealer, RoleSupport, RoleMelee, RoleRanged}
+
+// roleKeywords maps weapon line tokens in an item family to a role. Artifact variants
+// share their line's token, e.g. MAIN_HOLYSTAFF_MORGANA.
+var roleKeywords = map[string]string{
+	"HOLYSTAFF":         RoleHealer,
+	"DIVINESTAFF":       RoleHealer,
+	"NATURESTAFF":       RoleHealer,
+	"WILDSTAFF":         RoleHealer,
+	"ARCANESTAFF":       RoleSupport,
+	"ENIGMATICSTAFF":    RoleSupport,
+	"MACE":              RoleTank,
+	"FLAIL":             RoleTank,
+	"HAMMER":            RoleTank,
+	"POLEHAMMER":        RoleTank,
+	"RAM":               RoleTank,
+	"QUARTERSTAFF":      RoleTank,
+	"IRONCLADEDSTAFF":   RoleTank,
+	"BOW":               RoleRanged,
+	"LONGBOW":           RoleRanged,
+	"WARBOW":            RoleRanged,
+	"CROSSBOW":          RoleRanged,
+	"CROSSBOWLARGE":     RoleRanged,
+	"REPEATINGCROSSBOW": RoleRanged,
+	"FIRESTAFF":         RoleRanged,
+	"INFERNOSTAFF":      RoleRanged,
+	"FROSTSTAFF":        RoleRanged,
+	"GLACIALSTAFF":      RoleRanged,
+	"CURSEDSTAFF":       RoleRanged,
+	"DEMONICSTAFF":      RoleRanged,
+}
+
+func isRole(role string) bool {
+	for _, r := range Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Role classifies a weapon id. Role tags in the catalogue win; otherwise the weapon
+// line decides, and any other main hand weapon is melee. Non-weapons have no role.
+func (c *Catalogue) Role(id string) string {
+	if m, ok := c.Metadata(id); ok {
+		for _, role := range m.Roles {
+			if isRole(role) {
+				return role
+			}
+		}
+	}
+
+	item := Parse(id)
+	if item.Slot != SlotMainHand {
+		return ""
+	}
+	for _, token := range strings.Split(item.Family, "_") {
+		if role, ok := roleKeywords[token]; ok {
+			return role
+		}
+	}
+	return RoleMelee
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"time"
+)
+
+type AllianceGraphNode struct {
+	AllianceName string `gorm:"column:alliance_name"`
+	Battles      int64  `gorm:"column:battles"`
+	MaxPlayers   int32  `gorm:"column:max_players"`
+	KillFame     int64  `gorm:"column:kill_fame"`
+	DeathFame    int64  `gorm:"column:death_fame"`
+}
+
+// AllianceGraphEdge links two alliances. Source sorts before Target, so each pair
+// appears once per edge type.
+type AllianceGraphEdge struct {
+	Source string `gorm:"column:source"`
+	Target string `gorm:"column:target"`
+	Weight int64  `gorm:"column:weight"`
+	Fame   int64  `gorm:"column:fame"`
+}
+
+// GetAllianceGraphNodes returns the alliances that fought in [from, to), most battles first.
+func (p *Postgres) GetAllianceGraphNodes(ctx context.Context, region string, from, to time.Time, limit int) ([]AllianceGraphNode, error) {
+	var nodes []AllianceGraphNode
+	err := p.db.WithContext(ctx).Raw(`
+SELECT
+    alliance_name,
+    COUNT(*) AS battles,
+    MAX(player_count) AS max_players,
+    SUM(kill_fame) AS kill_fame,
+    COALESCE(SUM(death_fame), 0) AS death_fame
+FROM battle_alliance_stats
+WHERE region = ?
+  AND start_time >= ?
+  AND start_time < ?
+  AND alliance_name <> ''
+GROUP BY alliance_name
+ORDER BY battles DESC, kill_fame DESC
+LIMIT ?;
+	`, region, from, to, limit).Scan(&nodes).Error
+
+	return nodes, err
+}
+
+// GetAllianceAllyEdges counts the battles in [from, to) where two alliances were on
+// the same side. Fame is not tracked for ally edges.
+func (p *Postgres) GetAllianceAllyEdges(ctx context.Context, region string, from, to time.Time) ([]AllianceGraphEdge, error) {
+	var edges []AllianceGraphEdge
+	err := p.db.WithContext(ctx).Raw(`
+SELECT
+    a.entity_name AS source,
+    b.entity_name AS target,
+    COUNT(*) AS weight,
+    0 AS fame
+FROM battle_sides a
+JOIN battle_sides b
+  ON b.region = a.region
+ AND b.battle_id = a.battle_id
+ AND b.entity_type = a.entity_type
+ AND b.side = a.side
+ AND b.entity_name > a.entity_name
+WHERE a.region = ?
+  AND a.entity_type = ?
+  AND a.start_time >= ?
+  AND a.start_time < ?
+GROUP BY 1, 2;
+	`, region, SideEntityAlliance, from, to).Scan(&edges).Error
+
+	return edges, err
+}
+
+// GetAllianceEnemyEdges counts the kills two alliances traded in [from, to), in
+// either direction.
+func (p *Postgres) GetAllianceEnemyEdges(ctx context.Context, region string, from, to time.Time) ([]AllianceGraphEdge, error) {
+	var edges []AllianceGraphEdge
+	err := p.db.WithContext(ctx).Raw(`
+SELECT
+    LEAST(killer_alliance, victim_alliance) AS source,
+    GREATEST(killer_alliance, victim_alliance) AS target,
+    COUNT(*) AS weight,
+    COALESCE(SUM(fame), 0) AS fame
+FROM battle_kills
+WHERE region = ?
+  AND ts >= ?
+  AND ts < ?
+  AND killer_alliance IS NOT NULL
+  AND victim_alliance IS NOT NULL
+  AND killer_alliance <> victim_alliance
+GROUP BY 1, 2;
+	`, region, from, to).Scan(&edges).Error
+
+	return edges, err
+}
@@ -24,6 +24,7 @@ type AllianceBattleSummary struct {
 	TotalDeathFame int64     `gorm:"column:total_death_fame"`
 	MaxPlayers     int32     `gorm:"column:max_players"`
 	LastBattleAt   time.Time `gorm:"column:last_battle_at"`
+	BattleRecord
 }
 
 func (p *Postgres) InsertBattleAllianceStats(stats []BattleAllianceStats) error {
@@ -62,24 +63,35 @@ func (p *Postgres) UpdateBattleAllianceStats(stats []BattleAllianceStats) error
 	})
 }
 
-func (p *Postgres) GetBattleSummariesByAlliance(region string, allianceName string, playerCount int, limit int, offset int) ([]BattleSummary, error) {
+func (p *Postgres) GetBattleSummariesByAlliance(region string, allianceName string, playerCount int, cursor *BattleCursor, limit int, offset int) ([]BattleSummary, error) {
+	args := []interface{}{region, allianceName, playerCount}
+	cursorClause := ""
+	if cursor != nil {
+		cursorClause = "AND (start_time, battle_id) < (?, ?)"
+		args = append(args, cursor.StartTime, cursor.BattleID)
+	}
+	args = append(args, limit, offset)
+
 	var summaries []BattleSummary
 	err := p.db.Raw(`
-SELECT bs.*
+SELECT bs.*, z.name AS zone_name, z.type AS zone_type
 FROM (
     SELECT region, battle_id, start_time
     FROM battle_alliance_stats
     WHERE region = ?
       AND alliance_name = ?
       AND player_count >= ?
-    ORDER BY start_time DESC
+      `+cursorClause+`
+    ORDER BY start_time DESC, battle_id DESC
     LIMIT ? OFFSET ?
 ) bas
 JOIN battle_summary bs
   ON bs.region = bas.region
  AND bs.battle_id = bas.battle_id
-ORDER BY bas.start_time DESC;
-	`, region, allianceName, playerCount, limit, offset).Scan(&summaries).Error
+LEFT JOIN zones z
+  ON z.cluster_id = bs.cluster_name
+ORDER BY bas.start_time DESC, bas.battle_id DESC;
+	`, args...).Scan(&summaries).Error
 
 	return summaries, err
 }
@@ -114,22 +126,26 @@ func (p *Postgres) GetTopAlliances(region string, limit int, offset int) ([]TopA
 	return stats, err
 }
 
-func (p *Postgres) GetAllianceBattleSummary(ctx context.Context, region string, allianceName string) (*AllianceBattleSummary, error) {
+// GetAllianceBattleSummary totals the alliance's battles since the given time. Wins,
+// losses and draws only count battles where the alliance was placed on a side.
+func (p *Postgres) GetAllianceBattleSummary(ctx context.Context, region string, allianceName string, since time.Time) (*AllianceBattleSummary, error) {
 	var summary AllianceBattleSummary
 	err := p.db.WithContext(ctx).Raw(`
 SELECT
-    COUNT(DISTINCT battle_id) AS battles,
-    SUM(kills) AS total_kills,
-    SUM(deaths) AS total_deaths,
-    SUM(kill_fame) AS total_kill_fame,
-    SUM(death_fame) AS total_death_fame,
-    MAX(player_count) AS max_players,
-    MAX(start_time) AS last_battle_at
-FROM battle_alliance_stats
-WHERE region = ?
-  AND alliance_name = ?
-  AND start_time >= now() - INTERVAL '30 days';
-	`, region, allianceName).Scan(&summary).Error
+    COUNT(DISTINCT s.battle_id) AS battles,
+    SUM(s.kills) AS total_kills,
+    SUM(s.deaths) AS total_deaths,
+    SUM(s.kill_fame) AS total_kill_fame,
+    SUM(s.death_fame) AS total_death_fame,
+    MAX(s.player_count) AS max_players,
+    MAX(s.start_time) AS last_battle_at,
+    `+battleRecordColumns+`
+FROM battle_alliance_stats s
+`+battleRecordJoins(SideEntityAlliance, "alliance_name")+`
+WHERE s.region = ?
+  AND s.alliance_name = ?
+  AND s.start_time >= ?;
+	`, region, allianceName, since).Scan(&summary).Error
 	if err != nil {
 		return nil, err
 	}
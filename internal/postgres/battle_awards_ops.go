@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReplaceBattleAwards stores a battle's awards, replacing any from an earlier run.
+func (p *Postgres) ReplaceBattleAwards(region Region, battleID int64, awards []BattleAward) error {
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("region = ? AND battle_id = ?", region, battleID).
+			Delete(&BattleAward{}).Error; err != nil {
+			return err
+		}
+
+		if len(awards) == 0 {
+			return nil
+		}
+		return tx.Create(&awards).Error
+	})
+}
+
+func (p *Postgres) GetBattleAwardsByIDs(ctx context.Context, region string, battleIDs []int64) ([]BattleAward, error) {
+	var awards []BattleAward
+	err := p.db.WithContext(ctx).
+		Where("region = ? AND battle_id IN ?", region, battleIDs).
+		Order("battle_id, side, award").
+		Find(&awards).Error
+	return awards, err
+}
+
+// PlayerAwardCounts is how often a player was picked for each award. MVPs and the
+// top awards are battle-wide; SideMVPs counts MVP of their side only.
+type PlayerAwardCounts struct {
+	PlayerName string `gorm:"column:player_name"`
+	MVPs       int64  `gorm:"column:mvps"`
+	SideMVPs   int64  `gorm:"column:side_mvps"`
+	TopHealer  int64  `gorm:"column:top_healer"`
+	TopDamage  int64  `gorm:"column:top_damage"`
+	TopTank    int64  `gorm:"column:top_tank"`
+}
+
+// GetPlayerAwardCounts ranks players by MVP count since the given time. An empty
+// playerName lists everyone.
+func (p *Postgres) GetPlayerAwardCounts(ctx context.Context, region string, playerName string, since time.Time, limit int, offset int) ([]PlayerAwardCounts, error) {
+	args := map[string]interface{}{
+		"region":  region,
+		"since":   since,
+		"mvp":     AwardMVP,
+		"healer":  AwardHealer,
+		"damage":  AwardDamage,
+		"tank":    AwardTank,
+		"limit":   limit,
+		"offset":  offset,
+		"name":    playerName,
+		"anyName": playerName == "",
+	}
+
+	var counts []PlayerAwardCounts
+	err := p.db.WithContext(ctx).Raw(`
+SELECT
+    player_name,
+    COUNT(*) FILTER (WHERE award = @mvp AND side = 0) AS mvps,
+    COUNT(*) FILTER (WHERE award = @mvp AND side <> 0) AS side_mvps,
+    COUNT(*) FILTER (WHERE award = @healer AND side = 0) AS top_healer,
+    COUNT(*) FILTER (WHERE award = @damage AND side = 0) AS top_damage,
+    COUNT(*) FILTER (WHERE award = @tank AND side = 0) AS top_tank
+FROM battle_awards
+WHERE region = @region
+AND start_time >= @since
+AND (@anyName OR player_name = @name)
+GROUP BY player_name
+ORDER BY mvps DESC, side_mvps DESC, player_name
+LIMIT @limit OFFSET @offset
+`, args).Scan(&counts).Error
+
+	return counts, err
+}
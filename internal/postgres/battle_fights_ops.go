@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// FightCandidate is a battle along with who took part, used to group battles into fights.
+type FightCandidate struct {
+	BattleID    int64          `gorm:"column:battle_id"`
+	StartTime   time.Time      `gorm:"column:start_time"`
+	EndTime     time.Time      `gorm:"column:end_time"`
+	ClusterName *string        `gorm:"column:cluster_name"`
+	Alliances   pq.StringArray `gorm:"column:alliances;type:text[]"`
+	Guilds      pq.StringArray `gorm:"column:guilds;type:text[]"`
+
+	// Fight the battle was grouped into on a previous run, if any
+	FightID *int64 `gorm:"column:fight_id"`
+}
+
+// GetFightCandidates returns the battles started since the given time, plus every
+// battle already in a fight with one of them. Regrouping a fight without its older
+// battles would leave them behind in a fight of their own.
+func (p *Postgres) GetFightCandidates(ctx context.Context, region Region, since time.Time) ([]FightCandidate, error) {
+	var candidates []FightCandidate
+	err := p.db.WithContext(ctx).Raw(`
+SELECT
+    bs.battle_id,
+    bs.start_time,
+    bs.end_time,
+    bs.cluster_name,
+    ARRAY(
+        SELECT bas.alliance_name
+        FROM battle_alliance_stats bas
+        WHERE bas.region = bs.region
+          AND bas.battle_id = bs.battle_id
+          AND bas.alliance_name <> ''
+    ) AS alliances,
+    ARRAY(
+        SELECT bgs.guild_name
+        FROM battle_guild_stats bgs
+        WHERE bgs.region = bs.region
+          AND bgs.battle_id = bs.battle_id
+          AND bgs.guild_name <> ''
+    ) AS guilds,
+    bf.fight_id
+FROM battle_summary bs
+LEFT JOIN battle_fights bf
+  ON bf.region = bs.region
+ AND bf.battle_id = bs.battle_id
+WHERE bs.region = ?
+  AND (
+      bs.start_time >= ?
+      OR bf.fight_id IN (
+          SELECT fight_id
+          FROM battle_fights
+          WHERE region = ?
+            AND start_time >= ?
+      )
+  )
+ORDER BY bs.start_time ASC, bs.battle_id ASC;
+	`, region, since, region, since).Scan(&candidates).Error
+
+	return candidates, err
+}
+
+// ReplaceBattleFights stores the fights found among battleIDs, dropping any rows left
+// from earlier runs for those battles, so a battle that moved to another fight or is
+// no longer grouped doesn't keep its old fight.
+func (p *Postgres) ReplaceBattleFights(ctx context.Context, region Region, battleIDs []int64, fights []BattleFight) error {
+	if len(battleIDs) == 0 {
+		return nil
+	}
+
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("region = ? AND battle_id IN ?", region, battleIDs).
+			Delete(&BattleFight{}).Error; err != nil {
+			return err
+		}
+
+		if len(fights) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(fights, 500).Error
+	})
+}
+
+// GetFightBattleIDs returns the fight battleID was grouped into and every battle in it,
+// or nil if the battle hasn't been grouped.
+func (p *Postgres) GetFightBattleIDs(ctx context.Context, region string, battleID int64) (int64, []int64, error) {
+	var rows []struct {
+		FightID  int64 `gorm:"column:fight_id"`
+		BattleID int64 `gorm:"column:battle_id"`
+	}
+	err := p.db.WithContext(ctx).Raw(`
+SELECT bf.fight_id, bf.battle_id
+FROM battle_fights bf
+WHERE bf.region = ?
+  AND bf.fight_id = (
+      SELECT fight_id
+      FROM battle_fights
+      WHERE region = ?
+        AND battle_id = ?
+  )
+ORDER BY bf.start_time ASC, bf.battle_id ASC;
+	`, region, region, battleID).Scan(&rows).Error
+	if err != nil || len(rows) == 0 {
+		return 0, nil, err
+	}
+
+	battleIDs := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		battleIDs = append(battleIDs, row.BattleID)
+	}
+	return rows[0].FightID, battleIDs, nil
+}
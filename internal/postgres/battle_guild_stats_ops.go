@@ -34,6 +34,7 @@ type GuildBattleSummary struct {
 	TotalDeathFame int64     `gorm:"column:total_death_fame"`
 	MaxPlayers     int32     `gorm:"column:max_players"`
 	LastBattleAt   time.Time `gorm:"column:last_battle_at"`
+	BattleRecord
 }
 
 func (p *Postgres) InsertBattleGuildStats(stats []BattleGuildStats) error {
@@ -72,24 +73,35 @@ func (p *Postgres) UpdateBattleGuildStats(stats []BattleGuildStats) error {
 	})
 }
 
-func (p *Postgres) GetBattleSummariesByGuild(region string, guildName string, playerCount int, limit int, offset int) ([]BattleSummary, error) {
+func (p *Postgres) GetBattleSummariesByGuild(region string, guildName string, playerCount int, cursor *BattleCursor, limit int, offset int) ([]BattleSummary, error) {
+	args := []interface{}{region, guildName, playerCount}
+	cursorClause := ""
+	if cursor != nil {
+		cursorClause = "AND (start_time, battle_id) < (?, ?)"
+		args = append(args, cursor.StartTime, cursor.BattleID)
+	}
+	args = append(args, limit, offset)
+
 	var summaries []BattleSummary
 	err := p.db.Raw(`
-SELECT bs.*
+SELECT bs.*, z.name AS zone_name, z.type AS zone_type
 FROM (
     SELECT region, battle_id, start_time
     FROM battle_guild_stats
     WHERE region = ?
       AND guild_name = ?
       AND player_count >= ?
-    ORDER BY start_time DESC
+      `+cursorClause+`
+    ORDER BY start_time DESC, battle_id DESC
     LIMIT ? OFFSET ?
 ) bgs
 JOIN battle_summary bs
   ON bs.region = bgs.region
  AND bs.battle_id = bgs.battle_id
-ORDER BY bgs.start_time DESC;
-	`, region, guildName, playerCount, limit, offset).Scan(&summaries).Error
+LEFT JOIN zones z
+  ON z.cluster_id = bs.cluster_name
+ORDER BY bgs.start_time DESC, bgs.battle_id DESC;
+	`, args...).Scan(&summaries).Error
 
 	return summaries, err
 }
@@ -146,22 +158,26 @@ func (p *Postgres) GetTopGuilds(region string, limit int, offset int) ([]TopGuil
 	return stats, err
 }
 
-func (p *Postgres) GetGuildBattleSummary(ctx context.Context, region string, guildName string) (*GuildBattleSummary, error) {
+// GetGuildBattleSummary totals the guild's battles since the given time. Wins,
+// losses and draws only count battles where the guild was placed on a side.
+func (p *Postgres) GetGuildBattleSummary(ctx context.Context, region string, guildName string, since time.Time) (*GuildBattleSummary, error) {
 	var summary GuildBattleSummary
 	err := p.db.WithContext(ctx).Raw(`
 SELECT
-    COUNT(DISTINCT battle_id) AS battles,
-    SUM(kills) AS total_kills,
-    SUM(deaths) AS total_deaths,
-    SUM(kill_fame) AS total_kill_fame,
-    SUM(death_fame) AS total_death_fame,
-    MAX(player_count) AS max_players,
-    MAX(start_time) AS last_battle_at
-FROM battle_guild_stats
-WHERE region = ?
-  AND guild_name = ?
-  AND start_time >= now() - INTERVAL '30 days';
-	`, region, guildName).Scan(&summary).Error
+    COUNT(DISTINCT s.battle_id) AS battles,
+    SUM(s.kills) AS total_kills,
+    SUM(s.deaths) AS total_deaths,
+    SUM(s.kill_fame) AS total_kill_fame,
+    SUM(s.death_fame) AS total_death_fame,
+    MAX(s.player_count) AS max_players,
+    MAX(s.start_time) AS last_battle_at,
+    `+battleRecordColumns+`
+FROM battle_guild_stats s
+`+battleRecordJoins(SideEntityGuild, "guild_name")+`
+WHERE s.region = ?
+  AND s.guild_name = ?
+  AND s.start_time >= ?;
+	`, region, guildName, since).Scan(&summary).Error
 	if err != nil {
 		return nil, err
 	}
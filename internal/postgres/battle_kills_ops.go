@@ -30,3 +30,77 @@ func (p *Postgres) GetBattleKillsByIDs(ctx context.Context, region string, battl
 		Find(&kills).Error
 	return kills, err
 }
+
+func (p *Postgres) InsertBattleKillParticipants(participants []BattleKillParticipant) error {
+	if len(participants) == 0 {
+		return nil
+	}
+
+	return p.db.Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(&participants, 500).Error
+}
+
+// KillInvolvement is a guild that took part in a kill, as killer or assist.
+type KillInvolvement struct {
+	EventID   int64  `gorm:"column:event_id"`
+	GuildName string `gorm:"column:guild_name"`
+}
+
+// GetBattleKillInvolvement returns each guild involved in each kill of the given
+// battles, once per kill.
+func (p *Postgres) GetBattleKillInvolvement(ctx context.Context, region string, battleIDs []int64) ([]KillInvolvement, error) {
+	var involvement []KillInvolvement
+	err := p.db.WithContext(ctx).Raw(`
+SELECT DISTINCT event_id, guild_name
+FROM (
+    SELECT event_id, guild_name
+    FROM battle_kill_participants
+    WHERE region = ?
+      AND battle_id IN ?
+    UNION ALL
+    SELECT event_id, killer_guild
+    FROM battle_kills
+    WHERE region = ?
+      AND battle_id IN ?
+) involved
+WHERE guild_name IS NOT NULL;
+	`, region, battleIDs, region, battleIDs).Scan(&involvement).Error
+
+	return involvement, err
+}
+
+// KillMatrixEntry is the number of kills and fame one side took from another.
+type KillMatrixEntry struct {
+	Killer string `gorm:"column:killer"`
+	Victim string `gorm:"column:victim"`
+	Kills  int64  `gorm:"column:kills"`
+	Fame   int64  `gorm:"column:fame"`
+}
+
+func (p *Postgres) GetBattleAllianceKillMatrix(ctx context.Context, region string, battleIDs []int64) ([]KillMatrixEntry, error) {
+	return p.getBattleKillMatrix(ctx, region, battleIDs, "killer_alliance", "victim_alliance")
+}
+
+func (p *Postgres) GetBattleGuildKillMatrix(ctx context.Context, region string, battleIDs []int64) ([]KillMatrixEntry, error) {
+	return p.getBattleKillMatrix(ctx, region, battleIDs, "killer_guild", "victim_guild")
+}
+
+// getBattleKillMatrix groups kills by the given killer and victim columns. Players
+// without a guild or alliance are grouped under an empty name.
+func (p *Postgres) getBattleKillMatrix(ctx context.Context, region string, battleIDs []int64, killerColumn, victimColumn string) ([]KillMatrixEntry, error) {
+	var entries []KillMatrixEntry
+	err := p.db.WithContext(ctx).Raw(`
+SELECT
+    COALESCE(`+killerColumn+`, '') AS killer,
+    COALESCE(`+victimColumn+`, '') AS victim,
+    COUNT(*) AS kills,
+    COALESCE(SUM(fame), 0) AS fame
+FROM battle_kills
+WHERE region = ?
+  AND battle_id IN ?
+GROUP BY 1, 2
+ORDER BY fame DESC;
+	`, region, battleIDs).Scan(&entries).Error
+
+	return entries, err
+}
@@ -64,6 +64,11 @@ func (p *Postgres) UpdateBattlePlayerStats(stats []BattlePlayerStats) error {
 			updates["weapon"] = stat.Weapon
 			updates["damage"] = stat.Damage
 			updates["heal"] = stat.Heal
+			updates["assists"] = stat.Assists
+			updates["silver_lost"] = stat.SilverLost
+			updates["kill_streak"] = stat.KillStreak
+			updates["multi_kills"] = stat.MultiKills
+			updates["best_multi_kill"] = stat.BestMultiKill
 
 			if err := tx.Model(&BattlePlayerStats{}).
 				Where("region = ? AND battle_id = ? AND player_name = ?", stat.Region, stat.BattleID, stat.PlayerName).
@@ -75,23 +80,32 @@ func (p *Postgres) UpdateBattlePlayerStats(stats []BattlePlayerStats) error {
 	})
 }
 
-func (p *Postgres) GetBattleSummariesByPlayer(region string, playerName string, playerCount int, limit int, offset int) ([]BattleSummary, error) {
+func (p *Postgres) GetBattleSummariesByPlayer(region string, playerName string, playerCount int, cursor *BattleCursor, limit int, offset int) ([]BattleSummary, error) {
+	args := []interface{}{region, playerName, playerCount}
+	cursorClause := ""
+	if cursor != nil {
+		cursorClause = "AND (bps.start_time, bps.battle_id) < (?, ?)"
+		args = append(args, cursor.StartTime, cursor.BattleID)
+	}
+	args = append(args, limit, offset)
+
+	// Walk the player's battles newest first so the scan stops once the page is full
 	var summaries []BattleSummary
 	err := p.db.Raw(`
-		SELECT bs.*
-		FROM (
-		    SELECT region, battle_id
-		    FROM battle_player_stats
-		    WHERE region = ?
-		    AND player_name = ?
-		) bps
+		SELECT bs.*, z.name AS zone_name, z.type AS zone_type
+		FROM battle_player_stats bps
 		JOIN battle_summary bs
 		ON bs.region = bps.region
 		AND bs.battle_id = bps.battle_id
-		WHERE bs.total_players >= ?
-		ORDER BY bs.start_time DESC
+		LEFT JOIN zones z
+		ON z.cluster_id = bs.cluster_name
+		WHERE bps.region = ?
+		AND bps.player_name = ?
+		AND bs.total_players >= ?
+		`+cursorClause+`
+		ORDER BY bps.start_time DESC, bps.battle_id DESC
 		LIMIT ? OFFSET ?
-	`, region, playerName, playerCount, limit, offset).Scan(&summaries).Error
+	`, args...).Scan(&summaries).Error
 
 	return summaries, err
 }
@@ -105,6 +119,16 @@ func (p *Postgres) GetBattlePlayerStatsByIDs(ctx context.Context, region string,
 	return stats, err
 }
 
+// GetPlayerBattleStatsByIDs returns one player's stats in each of the given battles.
+func (p *Postgres) GetPlayerBattleStatsByIDs(ctx context.Context, region string, playerName string, battleIDs []int64) ([]BattlePlayerStats, error) {
+	var stats []BattlePlayerStats
+	err := p.db.WithContext(ctx).
+		Where("region = ? AND player_name = ? AND battle_id IN ?", region, playerName, battleIDs).
+		Find(&stats).Error
+
+	return stats, err
+}
+
 func (p *Postgres) GetAlliancePlayerStats(region string, allianceName string) ([]AlliancePlayerStats, error) {
 	var stats []AlliancePlayerStats
 	err := p.db.Raw(`
@@ -168,3 +192,33 @@ func (p *Postgres) GetTopPlayers(region string, limit int, offset int) ([]TopPla
 
 	return stats, err
 }
+
+// PlayerBattleWeapon is the weapon a player used in a battle.
+type PlayerBattleWeapon struct {
+	BattleID   int64   `gorm:"column:battle_id"`
+	PlayerName string  `gorm:"column:player_name"`
+	Weapon     *string `gorm:"column:weapon"`
+}
+
+// GetGuildRecentWeapons returns the weapons of the guild's players in its last
+// `battles` battles with at least playerCount members present.
+func (p *Postgres) GetGuildRecentWeapons(ctx context.Context, region string, guildName string, playerCount int, battles int) ([]PlayerBattleWeapon, error) {
+	var weapons []PlayerBattleWeapon
+	err := p.db.WithContext(ctx).Raw(`
+SELECT bps.battle_id, bps.player_name, bps.weapon
+FROM battle_player_stats bps
+WHERE bps.region = ?
+  AND bps.guild_name = ?
+  AND bps.battle_id IN (
+    SELECT bgs.battle_id
+    FROM battle_guild_stats bgs
+    WHERE bgs.region = ?
+      AND bgs.guild_name = ?
+      AND bgs.player_count >= ?
+    ORDER BY bgs.start_time DESC
+    LIMIT ?
+  );
+	`, region, guildName, region, guildName, playerCount, battles).Scan(&weapons).Error
+
+	return weapons, err
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+)
+
+// BattleRecord is an alliance or guild's result over a set of battles, using the
+// winning side stored on each battle summary.
+type BattleRecord struct {
+	Wins             int64   `gorm:"column:wins"`
+	Losses           int64   `gorm:"column:losses"`
+	Draws            int64   `gorm:"column:draws"`
+	WinRate          float64 `gorm:"column:win_rate"`
+	FameDifferential int64   `gorm:"column:fame_differential"`
+}
+
+type WinRateStats struct {
+	Name    string `gorm:"column:name"`
+	Battles int64  `gorm:"column:battles"`
+	BattleRecord
+}
+
+// battleRecordColumns aggregates BattleRecord from rows of a stats table aliased s,
+// joined by battleRecordJoins.
+const battleRecordColumns = `
+    COUNT(*) FILTER (WHERE bs.winning_side > 0 AND bs.winning_side = sd.side) AS wins,
+    COUNT(*) FILTER (WHERE bs.winning_side > 0 AND bs.winning_side <> sd.side) AS losses,
+    COUNT(*) FILTER (WHERE bs.winning_side = 0 AND sd.side IS NOT NULL) AS draws,
+    COALESCE(
+        COUNT(*) FILTER (WHERE bs.winning_side > 0 AND bs.winning_side = sd.side)::float8
+        / NULLIF(COUNT(*) FILTER (WHERE bs.winning_side IS NOT NULL AND sd.side IS NOT NULL), 0),
+        0
+    ) AS win_rate,
+    COALESCE(SUM(s.kill_fame), 0) - COALESCE(SUM(s.death_fame), 0) AS fame_differential`
+
+func battleRecordJoins(entityType, nameColumn string) string {
+	return fmt.Sprintf(`
+LEFT JOIN battle_sides sd
+  ON sd.region = s.region
+ AND sd.battle_id = s.battle_id
+ AND sd.entity_type = '%s'
+ AND sd.entity_name = s.%s
+LEFT JOIN battle_summary bs
+  ON bs.region = s.region
+ AND bs.battle_id = s.battle_id`, entityType, nameColumn)
+}
+
+func (p *Postgres) GetAllianceWinRates(region string, since time.Time, minBattles int, limit int, offset int) ([]WinRateStats, error) {
+	return p.getWinRates("battle_alliance_stats", SideEntityAlliance, "alliance_name", region, since, minBattles, limit, offset)
+}
+
+func (p *Postgres) GetGuildWinRates(region string, since time.Time, minBattles int, limit int, offset int) ([]WinRateStats, error) {
+	return p.getWinRates("battle_guild_stats", SideEntityGuild, "guild_name", region, since, minBattles, limit, offset)
+}
+
+// getWinRates ranks entities by win rate over their decided battles (wins, losses and
+// draws), leaving out those with fewer than minBattles.
+func (p *Postgres) getWinRates(table, entityType, nameColumn, region string, since time.Time, minBattles, limit, offset int) ([]WinRateStats, error) {
+	var stats []WinRateStats
+	err := p.db.Raw(`
+SELECT
+    s.`+nameColumn+` AS name,
+    COUNT(*) AS battles,
+    `+battleRecordColumns+`
+FROM `+table+` s
+`+battleRecordJoins(entityType, nameColumn)+`
+WHERE s.region = ?
+  AND s.start_time >= ?
+  AND s.`+nameColumn+` <> ''
+  AND sd.side IS NOT NULL
+  AND bs.winning_side IS NOT NULL
+GROUP BY s.`+nameColumn+`
+HAVING COUNT(*) >= ?
+ORDER BY win_rate DESC, battles DESC, name
+LIMIT ? OFFSET ?
+	`, region, since, minBattles, limit, offset).Scan(&stats).Error
+
+	return stats, err
+}
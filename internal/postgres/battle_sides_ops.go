@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// ReplaceBattleSides stores the inferred sides of a battle, replacing any from an
+// earlier run, and records the winning side on the battle summary.
+func (p *Postgres) ReplaceBattleSides(region Region, battleID int64, sides []BattleSideAssignment, winningSide *int16) error {
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("region = ? AND battle_id = ?", region, battleID).
+			Delete(&BattleSideAssignment{}).Error; err != nil {
+			return err
+		}
+
+		if len(sides) > 0 {
+			if err := tx.Create(&sides).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&BattleSummary{}).
+			Where("region = ? AND battle_id = ?", region, battleID).
+			Update("winning_side", winningSide).Error
+	})
+}
+
+func (p *Postgres) GetBattleSidesByIDs(ctx context.Context, region string, battleIDs []int64) ([]BattleSideAssignment, error) {
+	var sides []BattleSideAssignment
+	err := p.db.WithContext(ctx).
+		Where("region = ? AND battle_id IN ?", region, battleIDs).
+		Find(&sides).Error
+	return sides, err
+}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -22,11 +23,76 @@ func (p *Postgres) InsertBattleSummaries(summaries []BattleSummary) error {
 	})
 }
 
-func (p *Postgres) GetBattleSummariesByRegion(region string, limit, offset, minTotalPlayers int) ([]BattleSummary, error) {
+// BattleCursor is the keyset position of the last battle on a page. Battles are listed
+// newest first, so the next page holds battles that sort strictly after it.
+type BattleCursor struct {
+	StartTime time.Time
+	BattleID  int64
+}
+
+// BattleSummaryFilter narrows the battles listed by GetBattleSummariesByRegion.
+// Zero values are ignored. Every alliance and guild listed must have taken part.
+type BattleSummaryFilter struct {
+	MinTotalPlayers int
+	MinTotalKills   int
+	MinTotalFame    int64
+	From            *time.Time
+	To              *time.Time
+	Alliances       []string
+	Guilds          []string
+
+	// Zone type (e.g. "black"), zone name or cluster id
+	Zone string
+}
+
+func (p *Postgres) GetBattleSummariesByRegion(region string, filter BattleSummaryFilter, cursor *BattleCursor, limit, offset int) ([]BattleSummary, error) {
 	var summaries []BattleSummary
-	err := p.db.
-		Where("region = ? AND total_players >= ?", region, minTotalPlayers).
-		Order("start_time DESC").Limit(limit).
+	query := p.db.
+		Table("battle_summary bs").
+		Select("bs.*, z.name AS zone_name, z.type AS zone_type").
+		Joins("LEFT JOIN zones z ON z.cluster_id = bs.cluster_name").
+		Where("bs.region = ? AND bs.total_players >= ?", region, filter.MinTotalPlayers)
+
+	if filter.MinTotalKills > 0 {
+		query = query.Where("bs.total_kills >= ?", filter.MinTotalKills)
+	}
+	if filter.MinTotalFame > 0 {
+		query = query.Where("bs.total_fame >= ?", filter.MinTotalFame)
+	}
+	if filter.From != nil {
+		query = query.Where("bs.start_time >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("bs.start_time < ?", *filter.To)
+	}
+
+	for _, alliance := range filter.Alliances {
+		query = query.Where(`EXISTS (
+    SELECT 1 FROM battle_alliance_stats bas
+    WHERE bas.region = bs.region AND bas.battle_id = bs.battle_id AND bas.alliance_name = ?
+)`, alliance)
+	}
+	for _, guild := range filter.Guilds {
+		query = query.Where(`EXISTS (
+    SELECT 1 FROM battle_guild_stats bgs
+    WHERE bgs.region = bs.region AND bgs.battle_id = bs.battle_id AND bgs.guild_name = ?
+)`, guild)
+	}
+
+	if filter.Zone != "" {
+		if IsValidZoneType(filter.Zone) {
+			query = query.Where("z.type = ?", filter.Zone)
+		} else {
+			query = query.Where("(LOWER(z.name) = LOWER(?) OR bs.cluster_name = ?)", filter.Zone, filter.Zone)
+		}
+	}
+
+	if cursor != nil {
+		query = query.Where("(bs.start_time, bs.battle_id) < (?, ?)", cursor.StartTime, cursor.BattleID)
+	}
+
+	err := query.
+		Order("bs.start_time DESC, bs.battle_id DESC").Limit(limit).
 		Offset(offset).
 		Find(&summaries).Error
 	return summaries, err
@@ -35,8 +101,16 @@ func (p *Postgres) GetBattleSummariesByRegion(region string, limit, offset, minT
 func (p *Postgres) GetBattleSummariesByIDs(ctx context.Context, region string, battleIDs []int64) ([]BattleSummary, error) {
 	var summaries []BattleSummary
 	err := p.db.WithContext(ctx).
-		Select("region, battle_id, start_time, end_time, total_players, total_kills, total_fame").
-		Where("region = ? AND battle_id IN ?", region, battleIDs).
+		Table("battle_summary bs").
+		Select("bs.region, bs.battle_id, bs.start_time, bs.end_time, bs.total_players, bs.total_kills, bs.total_fame, bs.cluster_name, bs.kill_area, bs.winning_side, z.name AS zone_name, z.type AS zone_type").
+		Joins("LEFT JOIN zones z ON z.cluster_id = bs.cluster_name").
+		Where("bs.region = ? AND bs.battle_id IN ?", region, battleIDs).
 		Find(&summaries).Error
 	return summaries, err
 }
+
+func (p *Postgres) UpdateBattleSummaryKillArea(region Region, battleID int64, killArea string) error {
+	return p.db.Model(&BattleSummary{}).
+		Where("region = ? AND battle_id = ?", region, battleID).
+		Update("kill_area", killArea).Error
+}
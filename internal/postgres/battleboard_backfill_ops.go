@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetBattleboardBackfill returns the checkpoint for a backfill of region back to since,
+// or a fresh checkpoint starting at offset 0 if none has been saved yet.
+func (p *Postgres) GetBattleboardBackfill(region Region, since time.Time) (*BattleboardBackfill, error) {
+	var backfill BattleboardBackfill
+	err := p.db.Where("region = ? AND since = ?", region, since).First(&backfill).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &BattleboardBackfill{Region: region, Since: since}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &backfill, nil
+}
+
+func (p *Postgres) SaveBattleboardBackfill(backfill *BattleboardBackfill) error {
+	backfill.UpdatedAt = time.Now().UTC()
+	return p.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "region"}, {Name: "since"}},
+		DoUpdates: clause.AssignmentColumns([]string{"next_offset", "oldest_start_time", "battles", "completed", "updated_at"}),
+	}).Create(backfill).Error
+}
@@ -25,6 +25,26 @@ WHERE start_time < now() - interval '1 year'`).Error; err != nil {
 			return err
 		}
 		if err := tx.Exec(`DELETE FROM battle_kills
+WHERE ts < now() - interval '1 year'`).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`DELETE FROM battle_kill_participants
+WHERE ts < now() - interval '1 year'`).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`DELETE FROM battle_loadouts
+WHERE ts < now() - interval '1 year'`).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`DELETE FROM kill_loadouts
+WHERE ts < now() - interval '1 year'`).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`DELETE FROM legendary_soul_sightings
+WHERE ts < now() - interval '1 year'`).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`DELETE FROM killboard_kills
 WHERE ts < now() - interval '1 year'`).Error; err != nil {
 			return err
 		}
@@ -32,6 +52,18 @@ WHERE ts < now() - interval '1 year'`).Error; err != nil {
 WHERE ts < now() - interval '1 year'`).Error; err != nil {
 			return err
 		}
+		if err := tx.Exec(`DELETE FROM battle_fights
+WHERE start_time < now() - interval '1 year'`).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`DELETE FROM battle_sides
+WHERE start_time < now() - interval '1 year'`).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`DELETE FROM battle_awards
+WHERE start_time < now() - interval '1 year'`).Error; err != nil {
+			return err
+		}
 		return nil
 	})
 }
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UpsertLegendarySouls stores the latest state of each soul and its sightings. A soul
+// is only overwritten by a sighting at least as recent as the stored one, so events
+// processed late don't roll it back.
+func (p *Postgres) UpsertLegendarySouls(souls []LegendarySoul, sightings []LegendarySoulSighting) error {
+	if len(souls) == 0 && len(sightings) == 0 {
+		return nil
+	}
+
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		if len(souls) > 0 {
+			if err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "region"}, {Name: "soul_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{
+					"item_type", "subtype", "era", "name", "quality", "crafted_by",
+					"attuned_player_id", "attuned_player_name", "attunement",
+					"attunement_spent", "pvp_fame_gained", "traits", "last_equipped", "last_seen",
+				}),
+				Where: clause.Where{Exprs: []clause.Expression{
+					clause.Expr{SQL: "legendary_souls.last_seen <= excluded.last_seen"},
+				}},
+			}).Create(&souls).Error; err != nil {
+				return err
+			}
+
+			// The update above skips older sightings, but an older battle processed late
+			// can still move first_seen back
+			for _, soul := range souls {
+				if err := tx.Model(&LegendarySoul{}).
+					Where("region = ? AND soul_id = ? AND first_seen > ?", soul.Region, soul.SoulID, soul.FirstSeen).
+					Update("first_seen", soul.FirstSeen).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		if len(sightings) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+				CreateInBatches(&sightings, 500).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (p *Postgres) GetTopLegendarySouls(ctx context.Context, region string, limit int, offset int) ([]LegendarySoul, error) {
+	var souls []LegendarySoul
+	err := p.db.WithContext(ctx).
+		Where("region = ?", region).
+		Order("pvp_fame_gained DESC, soul_id").
+		Limit(limit).
+		Offset(offset).
+		Find(&souls).Error
+	return souls, err
+}
+
+func (p *Postgres) GetLegendarySoul(ctx context.Context, region string, soulID string) (*LegendarySoul, error) {
+	var soul LegendarySoul
+	err := p.db.WithContext(ctx).
+		Where("region = ? AND soul_id = ?", region, soulID).
+		First(&soul).Error
+	if err != nil {
+		return nil, err
+	}
+	return &soul, nil
+}
+
+func (p *Postgres) GetLegendarySoulSightings(ctx context.Context, region string, soulID string, limit int) ([]LegendarySoulSighting, error) {
+	var sightings []LegendarySoulSighting
+	err := p.db.WithContext(ctx).
+		Where("region = ? AND soul_id = ?", region, soulID).
+		Order("ts DESC").
+		Limit(limit).
+		Find(&sightings).Error
+	return sightings, err
+}
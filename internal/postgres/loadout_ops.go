@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReplaceBattleLoadouts stores the loadouts seen in a battle, replacing any from an
+// earlier run.
+func (p *Postgres) ReplaceBattleLoadouts(region Region, battleID int64, items []BattleLoadoutItem) error {
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("region = ? AND battle_id = ?", region, battleID).
+			Delete(&BattleLoadoutItem{}).Error; err != nil {
+			return err
+		}
+
+		if len(items) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(&items, 500).Error
+	})
+}
+
+func (p *Postgres) InsertKillLoadouts(items []KillLoadoutItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	return p.db.Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(&items, 500).Error
+}
+
+func (p *Postgres) GetBattleLoadoutsByIDs(ctx context.Context, region string, battleIDs []int64) ([]BattleLoadoutItem, error) {
+	var items []BattleLoadoutItem
+	err := p.db.WithContext(ctx).
+		Where("region = ? AND battle_id IN ?", region, battleIDs).
+		Order("player_name, battle_id, slot").
+		Find(&items).Error
+	return items, err
+}
+
+func (p *Postgres) GetKillLoadoutsByEventIDs(ctx context.Context, region string, eventIDs []int64) ([]KillLoadoutItem, error) {
+	var items []KillLoadoutItem
+	err := p.db.WithContext(ctx).
+		Where("region = ? AND event_id IN ?", region, eventIDs).
+		Order("event_id, role, slot").
+		Find(&items).Error
+	return items, err
+}
@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/lib/pq"
@@ -198,26 +199,67 @@ func (Metrics) TableName() string {
 }
 
 type BattleSummary struct {
-	Region        Region         `gorm:"column:region;primaryKey;type:region_enum"`
-	BattleID      int64          `gorm:"column:battle_id;primaryKey"`
-	StartTime     time.Time      `gorm:"column:start_time;not null"`
-	EndTime       time.Time      `gorm:"column:end_time"`
-	TotalPlayers  int32          `gorm:"column:total_players;not null"`
-	TotalKills    int32          `gorm:"column:total_kills;not null"`
-	TotalFame     int64          `gorm:"column:total_fame;not null"`
+	Region       Region    `gorm:"column:region;primaryKey;type:region_enum"`
+	BattleID     int64     `gorm:"column:battle_id;primaryKey"`
+	StartTime    time.Time `gorm:"column:start_time;not null"`
+	EndTime      time.Time `gorm:"column:end_time"`
+	TotalPlayers int32     `gorm:"column:total_players;not null"`
+	TotalKills   int32     `gorm:"column:total_kills;not null"`
+	TotalFame    int64     `gorm:"column:total_fame;not null"`
+	ClusterName  *string   `gorm:"column:cluster_name"`
+	KillArea     *string   `gorm:"column:kill_area"`
+
+	// Side that won on kill fame, 0 for a draw, nil until sides are inferred
+	WinningSide *int16 `gorm:"column:winning_side"`
+
+	// Deprecated: "Name (count)" strings kept for older clients, use battle_alliance_stats
+	// and battle_guild_stats for structured per-battle lists.
 	AllianceNames pq.StringArray `gorm:"column:alliance_names;type:text[]"`
 	GuildNames    pq.StringArray `gorm:"column:guild_names;type:text[]"`
 	PlayerNames   pq.StringArray `gorm:"column:player_names;type:text[]"`
+
+	// Joined from zones, read-only
+	ZoneName *string `gorm:"column:zone_name;->"`
+	ZoneType *string `gorm:"column:zone_type;->"`
 }
 
 func (BattleSummary) TableName() string {
 	return "battle_summary"
 }
 
+type ZoneType string
+
+const (
+	ZoneTypeBlue       ZoneType = "blue"
+	ZoneTypeYellow     ZoneType = "yellow"
+	ZoneTypeRed        ZoneType = "red"
+	ZoneTypeBlack      ZoneType = "black"
+	ZoneTypeAvalonRoad ZoneType = "avalon_road"
+)
+
+func IsValidZoneType(zoneType string) bool {
+	switch ZoneType(zoneType) {
+	case ZoneTypeBlue, ZoneTypeYellow, ZoneTypeRed, ZoneTypeBlack, ZoneTypeAvalonRoad:
+		return true
+	}
+	return false
+}
+
+type Zone struct {
+	ClusterID string   `gorm:"column:cluster_id;primaryKey"`
+	Name      string   `gorm:"column:name;not null"`
+	Type      ZoneType `gorm:"column:type;not null"`
+}
+
+func (Zone) TableName() string {
+	return "zones"
+}
+
 type BattleAllianceStats struct {
 	Region       Region    `gorm:"column:region;primaryKey;type:region_enum"`
 	BattleID     int64     `gorm:"column:battle_id;primaryKey"`
 	AllianceName string    `gorm:"column:alliance_name;primaryKey"`
+	AllianceID   *string   `gorm:"column:alliance_id"`
 	StartTime    time.Time `gorm:"column:start_time"`
 	PlayerCount  int32     `gorm:"column:player_count"`
 	Kills        int32     `gorm:"column:kills"`
@@ -235,6 +277,7 @@ type BattleGuildStats struct {
 	Region       Region    `gorm:"column:region;primaryKey;type:region_enum"`
 	BattleID     int64     `gorm:"column:battle_id;primaryKey"`
 	GuildName    string    `gorm:"column:guild_name;primaryKey"`
+	GuildID      *string   `gorm:"column:guild_id"`
 	AllianceName *string   `gorm:"column:alliance_name"`
 	StartTime    time.Time `gorm:"column:start_time"`
 	PlayerCount  int32     `gorm:"column:player_count"`
@@ -264,6 +307,12 @@ type BattlePlayerStats struct {
 	Weapon       *string   `gorm:"column:weapon"`
 	Damage       *int64    `gorm:"column:damage"`
 	Heal         *int64    `gorm:"column:heal"`
+	Assists      *int32    `gorm:"column:assists"`
+	SilverLost   *int64    `gorm:"column:silver_lost"`
+
+	KillStreak    *int32 `gorm:"column:kill_streak"`
+	MultiKills    *int32 `gorm:"column:multi_kills"`
+	BestMultiKill *int32 `gorm:"column:best_multi_kill"`
 }
 
 func (BattlePlayerStats) TableName() string {
@@ -271,22 +320,91 @@ func (BattlePlayerStats) TableName() string {
 }
 
 type BattleKills struct {
-	Region       Region    `gorm:"column:region;type:region_enum"`
-	BattleID     int64     `gorm:"column:battle_id"`
-	TS           time.Time `gorm:"column:ts"`
-	KillerName   string    `gorm:"column:killer_name"`
-	KillerIP     int32     `gorm:"column:killer_ip"`
-	KillerWeapon string    `gorm:"column:killer_weapon"`
-	VictimName   string    `gorm:"column:victim_name"`
-	VictimIP     int32     `gorm:"column:victim_ip"`
-	VictimWeapon string    `gorm:"column:victim_weapon"`
-	Fame         int64     `gorm:"column:fame"`
+	Region         Region    `gorm:"column:region;primaryKey;type:region_enum"`
+	EventID        int64     `gorm:"column:event_id;primaryKey"`
+	BattleID       int64     `gorm:"column:battle_id"`
+	TS             time.Time `gorm:"column:ts"`
+	KillerID       *string   `gorm:"column:killer_id"`
+	KillerName     string    `gorm:"column:killer_name"`
+	KillerGuild    *string   `gorm:"column:killer_guild"`
+	KillerAlliance *string   `gorm:"column:killer_alliance"`
+	KillerIP       int32     `gorm:"column:killer_ip"`
+	KillerWeapon   string    `gorm:"column:killer_weapon"`
+	VictimID       *string   `gorm:"column:victim_id"`
+	VictimName     string    `gorm:"column:victim_name"`
+	VictimGuild    *string   `gorm:"column:victim_guild"`
+	VictimAlliance *string   `gorm:"column:victim_alliance"`
+	VictimIP       int32     `gorm:"column:victim_ip"`
+	VictimWeapon   string    `gorm:"column:victim_weapon"`
+	Fame           int64     `gorm:"column:fame"`
+	SilverLost     int64     `gorm:"column:silver_lost"`
 }
 
 func (BattleKills) TableName() string {
 	return "battle_kills"
 }
 
+// BattleKillParticipant is a player who dealt damage or healing towards a kill,
+// including the killer.
+type BattleKillParticipant struct {
+	Region       Region    `gorm:"column:region;primaryKey;type:region_enum"`
+	EventID      int64     `gorm:"column:event_id;primaryKey"`
+	PlayerName   string    `gorm:"column:player_name;primaryKey"`
+	BattleID     int64     `gorm:"column:battle_id"`
+	TS           time.Time `gorm:"column:ts"`
+	PlayerID     *string   `gorm:"column:player_id"`
+	GuildName    *string   `gorm:"column:guild_name"`
+	AllianceName *string   `gorm:"column:alliance_name"`
+	IP           int32     `gorm:"column:ip"`
+	Weapon       *string   `gorm:"column:weapon"`
+	Damage       int64     `gorm:"column:damage"`
+	Heal         int64     `gorm:"column:heal"`
+}
+
+func (BattleKillParticipant) TableName() string {
+	return "battle_kill_participants"
+}
+
+const (
+	LoadoutRoleKiller = "killer"
+	LoadoutRoleVictim = "victim"
+)
+
+// BattleLoadoutItem is one equipment slot of the loadout a player was last seen with
+// in a battle. Slots use the API's equipment keys, e.g. MainHand, Head, Mount.
+type BattleLoadoutItem struct {
+	Region     Region    `gorm:"column:region;primaryKey;type:region_enum"`
+	BattleID   int64     `gorm:"column:battle_id;primaryKey"`
+	PlayerName string    `gorm:"column:player_name;primaryKey"`
+	Slot       string    `gorm:"column:slot;primaryKey"`
+	ItemType   string    `gorm:"column:item_type;not null"`
+	Quality    int16     `gorm:"column:quality;not null"`
+	Count      int32     `gorm:"column:count;not null"`
+	TS         time.Time `gorm:"column:ts;not null"`
+}
+
+func (BattleLoadoutItem) TableName() string {
+	return "battle_loadouts"
+}
+
+// KillLoadoutItem is one equipment slot of the killer's or victim's loadout in a kill.
+type KillLoadoutItem struct {
+	Region     Region    `gorm:"column:region;primaryKey;type:region_enum"`
+	EventID    int64     `gorm:"column:event_id;primaryKey"`
+	Role       string    `gorm:"column:role;primaryKey"`
+	Slot       string    `gorm:"column:slot;primaryKey"`
+	BattleID   int64     `gorm:"column:battle_id;not null"`
+	PlayerName string    `gorm:"column:player_name;not null"`
+	ItemType   string    `gorm:"column:item_type;not null"`
+	Quality    int16     `gorm:"column:quality;not null"`
+	Count      int32     `gorm:"column:count;not null"`
+	TS         time.Time `gorm:"column:ts;not null"`
+}
+
+func (KillLoadoutItem) TableName() string {
+	return "kill_loadouts"
+}
+
 type BattleQueue struct {
 	Region     Region    `gorm:"column:region;primaryKey;type:region_enum"`
 	BattleID   int64     `gorm:"column:battle_id;primaryKey"`
@@ -298,3 +416,147 @@ type BattleQueue struct {
 func (BattleQueue) TableName() string {
 	return "battle_queue"
 }
+
+type BattleboardBackfill struct {
+	Region          Region     `gorm:"column:region;primaryKey;type:region_enum"`
+	Since           time.Time  `gorm:"column:since;primaryKey"`
+	NextOffset      int        `gorm:"column:next_offset;not null"`
+	OldestStartTime *time.Time `gorm:"column:oldest_start_time"`
+	Battles         int64      `gorm:"column:battles;not null"`
+	Completed       bool       `gorm:"column:completed;default:false"`
+	UpdatedAt       time.Time  `gorm:"column:updated_at;not null"`
+}
+
+func (BattleboardBackfill) TableName() string {
+	return "battleboard_backfill"
+}
+
+type BattleFight struct {
+	Region    Region    `gorm:"column:region;primaryKey;type:region_enum"`
+	BattleID  int64     `gorm:"column:battle_id;primaryKey"`
+	FightID   int64     `gorm:"column:fight_id;not null"`
+	StartTime time.Time `gorm:"column:start_time;not null"`
+}
+
+func (BattleFight) TableName() string {
+	return "battle_fights"
+}
+
+const (
+	SideEntityAlliance = "alliance"
+	SideEntityGuild    = "guild"
+)
+
+// BattleSideAssignment places an alliance or guild on a side of a battle. Sides are
+// numbered from 1; guilds in an alliance share their alliance's side.
+type BattleSideAssignment struct {
+	Region     Region    `gorm:"column:region;primaryKey;type:region_enum"`
+	BattleID   int64     `gorm:"column:battle_id;primaryKey"`
+	EntityType string    `gorm:"column:entity_type;primaryKey"`
+	EntityName string    `gorm:"column:entity_name;primaryKey"`
+	Side       int16     `gorm:"column:side;not null"`
+	StartTime  time.Time `gorm:"column:start_time;not null"`
+}
+
+func (BattleSideAssignment) TableName() string {
+	return "battle_sides"
+}
+
+const (
+	AwardMVP    = "mvp"
+	AwardHealer = "healer"
+	AwardDamage = "damage"
+	AwardTank   = "tank"
+)
+
+// BattleAward is a player picked out by the MVP scoring model, for the whole battle
+// (side 0) or for one inferred side.
+type BattleAward struct {
+	Region       Region    `gorm:"column:region;primaryKey;type:region_enum"`
+	BattleID     int64     `gorm:"column:battle_id;primaryKey"`
+	Side         int16     `gorm:"column:side;primaryKey"`
+	Award        string    `gorm:"column:award;primaryKey"`
+	PlayerName   string    `gorm:"column:player_name;not null"`
+	GuildName    *string   `gorm:"column:guild_name"`
+	AllianceName *string   `gorm:"column:alliance_name"`
+	Score        float64   `gorm:"column:score;not null"`
+	StartTime    time.Time `gorm:"column:start_time;not null"`
+}
+
+func (BattleAward) TableName() string {
+	return "battle_awards"
+}
+
+// LegendarySoul is the latest state seen of a legendary (artifact) weapon soul.
+type LegendarySoul struct {
+	Region            Region          `gorm:"column:region;primaryKey;type:region_enum"`
+	SoulID            string          `gorm:"column:soul_id;primaryKey"`
+	ItemType          string          `gorm:"column:item_type;not null"`
+	Subtype           int32           `gorm:"column:subtype"`
+	Era               int32           `gorm:"column:era"`
+	Name              *string         `gorm:"column:name"`
+	Quality           int32           `gorm:"column:quality"`
+	CraftedBy         *string         `gorm:"column:crafted_by"`
+	AttunedPlayerID   *string         `gorm:"column:attuned_player_id"`
+	AttunedPlayerName *string         `gorm:"column:attuned_player_name"`
+	Attunement        int64           `gorm:"column:attunement"`
+	AttunementSpent   int64           `gorm:"column:attunement_spent"`
+	PvPFameGained     int64           `gorm:"column:pvp_fame_gained"`
+	Traits            json.RawMessage `gorm:"column:traits;type:jsonb"`
+	LastEquipped      *time.Time      `gorm:"column:last_equipped"`
+	FirstSeen         time.Time       `gorm:"column:first_seen;not null"`
+	LastSeen          time.Time       `gorm:"column:last_seen;not null"`
+}
+
+func (LegendarySoul) TableName() string {
+	return "legendary_souls"
+}
+
+// LegendarySoulSighting records who carried a soul in an event, and its owner and
+// attunement at the time.
+type LegendarySoulSighting struct {
+	Region            Region    `gorm:"column:region;primaryKey;type:region_enum"`
+	SoulID            string    `gorm:"column:soul_id;primaryKey"`
+	EventID           int64     `gorm:"column:event_id;primaryKey"`
+	TS                time.Time `gorm:"column:ts;not null"`
+	HolderID          *string   `gorm:"column:holder_id"`
+	HolderName        string    `gorm:"column:holder_name;not null"`
+	AttunedPlayerID   *string   `gorm:"column:attuned_player_id"`
+	AttunedPlayerName *string   `gorm:"column:attuned_player_name"`
+	Attunement        int64     `gorm:"column:attunement"`
+	AttunementSpent   int64     `gorm:"column:attunement_spent"`
+	PvPFameGained     int64     `gorm:"column:pvp_fame_gained"`
+}
+
+func (LegendarySoulSighting) TableName() string {
+	return "legendary_soul_sightings"
+}
+
+// KillboardKill is an open world kill from the killboard, outside any battle.
+// GroupSize is the killer's party size and Participants the number of players
+// credited with the kill.
+type KillboardKill struct {
+	Region         Region    `gorm:"column:region;primaryKey;type:region_enum"`
+	EventID        int64     `gorm:"column:event_id;primaryKey"`
+	TS             time.Time `gorm:"column:ts;not null"`
+	KillerID       *string   `gorm:"column:killer_id"`
+	KillerName     string    `gorm:"column:killer_name;not null"`
+	KillerGuild    *string   `gorm:"column:killer_guild"`
+	KillerAlliance *string   `gorm:"column:killer_alliance"`
+	KillerIP       int32     `gorm:"column:killer_ip"`
+	KillerWeapon   *string   `gorm:"column:killer_weapon"`
+	VictimID       *string   `gorm:"column:victim_id"`
+	VictimName     string    `gorm:"column:victim_name;not null"`
+	VictimGuild    *string   `gorm:"column:victim_guild"`
+	VictimAlliance *string   `gorm:"column:victim_alliance"`
+	VictimIP       int32     `gorm:"column:victim_ip"`
+	VictimWeapon   *string   `gorm:"column:victim_weapon"`
+	GroupSize      int32     `gorm:"column:group_size;not null"`
+	Participants   int32     `gorm:"column:participants;not null"`
+	Fame           int64     `gorm:"column:fame;not null"`
+	KillArea       *string   `gorm:"column:kill_area"`
+}
+
+func (KillboardKill) TableName() string {
+	return "killboard_kills"
+}
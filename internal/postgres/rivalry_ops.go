@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"time"
+)
+
+// RivalryBattle is a battle where both rivals were present, with each one's totals
+// and the side it was placed on.
+type RivalryBattle struct {
+	BattleID     int64     `gorm:"column:battle_id"`
+	StartTime    time.Time `gorm:"column:start_time"`
+	TotalPlayers int32     `gorm:"column:total_players"`
+	ZoneName     *string   `gorm:"column:zone_name"`
+	WinningSide  *int16    `gorm:"column:winning_side"`
+	APlayers     int32     `gorm:"column:a_players"`
+	AKills       int32     `gorm:"column:a_kills"`
+	ADeaths      int32     `gorm:"column:a_deaths"`
+	AKillFame    int64     `gorm:"column:a_kill_fame"`
+	ADeathFame   int64     `gorm:"column:a_death_fame"`
+	ASide        *int16    `gorm:"column:a_side"`
+	BPlayers     int32     `gorm:"column:b_players"`
+	BKills       int32     `gorm:"column:b_kills"`
+	BDeaths      int32     `gorm:"column:b_deaths"`
+	BKillFame    int64     `gorm:"column:b_kill_fame"`
+	BDeathFame   int64     `gorm:"column:b_death_fame"`
+	BSide        *int16    `gorm:"column:b_side"`
+}
+
+func (p *Postgres) GetAllianceRivalryBattles(ctx context.Context, region string, a, b string, since time.Time) ([]RivalryBattle, error) {
+	return p.getRivalryBattles(ctx, "battle_alliance_stats", SideEntityAlliance, "alliance_name", region, a, b, since)
+}
+
+func (p *Postgres) GetGuildRivalryBattles(ctx context.Context, region string, a, b string, since time.Time) ([]RivalryBattle, error) {
+	return p.getRivalryBattles(ctx, "battle_guild_stats", SideEntityGuild, "guild_name", region, a, b, since)
+}
+
+func (p *Postgres) getRivalryBattles(ctx context.Context, table, entityType, nameColumn, region, a, b string, since time.Time) ([]RivalryBattle, error) {
+	var battles []RivalryBattle
+	err := p.db.WithContext(ctx).Raw(`
+SELECT
+    bs.battle_id,
+    bs.start_time,
+    bs.total_players,
+    bs.winning_side,
+    z.name AS zone_name,
+    sa.player_count AS a_players,
+    sa.kills AS a_kills,
+    sa.deaths AS a_deaths,
+    sa.kill_fame AS a_kill_fame,
+    COALESCE(sa.death_fame, 0) AS a_death_fame,
+    sda.side AS a_side,
+    sb.player_count AS b_players,
+    sb.kills AS b_kills,
+    sb.deaths AS b_deaths,
+    sb.kill_fame AS b_kill_fame,
+    COALESCE(sb.death_fame, 0) AS b_death_fame,
+    sdb.side AS b_side
+FROM `+table+` sa
+JOIN `+table+` sb
+  ON sb.region = sa.region
+ AND sb.battle_id = sa.battle_id
+ AND sb.`+nameColumn+` = ?
+JOIN battle_summary bs
+  ON bs.region = sa.region
+ AND bs.battle_id = sa.battle_id
+LEFT JOIN zones z
+  ON z.cluster_id = bs.cluster_name
+LEFT JOIN battle_sides sda
+  ON sda.region = sa.region
+ AND sda.battle_id = sa.battle_id
+ AND sda.entity_type = ?
+ AND sda.entity_name = sa.`+nameColumn+`
+LEFT JOIN battle_sides sdb
+  ON sdb.region = sb.region
+ AND sdb.battle_id = sb.battle_id
+ AND sdb.entity_type = ?
+ AND sdb.entity_name = sb.`+nameColumn+`
+WHERE sa.region = ?
+  AND sa.`+nameColumn+` = ?
+  AND sa.start_time >= ?
+ORDER BY bs.start_time DESC, bs.battle_id DESC;
+	`, b, entityType, entityType, region, a, since).Scan(&battles).Error
+
+	return battles, err
+}
+
+func (p *Postgres) GetAllianceRivalryKills(ctx context.Context, region string, battleIDs []int64, a, b string) ([]BattleKills, error) {
+	return p.getRivalryKills(ctx, region, battleIDs, "killer_alliance", "victim_alliance", a, b)
+}
+
+func (p *Postgres) GetGuildRivalryKills(ctx context.Context, region string, battleIDs []int64, a, b string) ([]BattleKills, error) {
+	return p.getRivalryKills(ctx, region, battleIDs, "killer_guild", "victim_guild", a, b)
+}
+
+// getRivalryKills returns the kills in the given battles where one rival killed the other.
+func (p *Postgres) getRivalryKills(ctx context.Context, region string, battleIDs []int64, killerColumn, victimColumn, a, b string) ([]BattleKills, error) {
+	var kills []BattleKills
+	err := p.db.WithContext(ctx).
+		Where("region = ? AND battle_id IN ?", region, battleIDs).
+		Where("("+killerColumn+" = ? AND "+victimColumn+" = ?) OR ("+killerColumn+" = ? AND "+victimColumn+" = ?)", a, b, b, a).
+		Order("ts").
+		Find(&kills).Error
+	return kills, err
+}
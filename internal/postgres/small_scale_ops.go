@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// Small-scale brackets, by the killer's party size for kills and by the number of
+// players credited for deaths.
+const (
+	SmallScaleSolo  = "solo"
+	SmallScaleSmall = "small"
+)
+
+func (p *Postgres) InsertKillboardKills(kills []KillboardKill) error {
+	if len(kills) == 0 {
+		return nil
+	}
+
+	return p.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&kills).Error
+}
+
+// SmallScaleStats is a player's open world record. Solo is a party of one for kills
+// and a single killer for deaths; small is 2-5. Ganks are kills or deaths with three
+// or more players credited.
+type SmallScaleStats struct {
+	PlayerName   string `gorm:"column:player_name"`
+	Kills        int64  `gorm:"column:kills"`
+	Deaths       int64  `gorm:"column:deaths"`
+	KillFame     int64  `gorm:"column:kill_fame"`
+	DeathFame    int64  `gorm:"column:death_fame"`
+	SoloKills    int64  `gorm:"column:solo_kills"`
+	SoloDeaths   int64  `gorm:"column:solo_deaths"`
+	SmallKills   int64  `gorm:"column:small_kills"`
+	SmallDeaths  int64  `gorm:"column:small_deaths"`
+	GankKills    int64  `gorm:"column:gank_kills"`
+	GankedDeaths int64  `gorm:"column:ganked_deaths"`
+}
+
+const smallScaleQuery = `
+WITH sided AS (
+    SELECT killer_name AS player_name, 1 AS kill, 0 AS death, group_size, participants, fame
+    FROM killboard_kills
+    WHERE region = @region AND ts >= @since %[1]s
+    UNION ALL
+    SELECT victim_name AS player_name, 0 AS kill, 1 AS death, group_size, participants, fame
+    FROM killboard_kills
+    WHERE region = @region AND ts >= @since %[2]s
+)
+SELECT
+    player_name,
+    SUM(kill) AS kills,
+    SUM(death) AS deaths,
+    COALESCE(SUM(fame) FILTER (WHERE kill = 1), 0) AS kill_fame,
+    COALESCE(SUM(fame) FILTER (WHERE death = 1), 0) AS death_fame,
+    COUNT(*) FILTER (WHERE kill = 1 AND group_size <= 1) AS solo_kills,
+    COUNT(*) FILTER (WHERE death = 1 AND participants <= 1) AS solo_deaths,
+    COUNT(*) FILTER (WHERE kill = 1 AND group_size BETWEEN 2 AND 5) AS small_kills,
+    COUNT(*) FILTER (WHERE death = 1 AND participants BETWEEN 2 AND 5) AS small_deaths,
+    COUNT(*) FILTER (WHERE kill = 1 AND participants >= 3) AS gank_kills,
+    COUNT(*) FILTER (WHERE death = 1 AND participants >= 3) AS ganked_deaths
+FROM sided
+GROUP BY player_name
+`
+
+func (p *Postgres) GetPlayerSmallScaleStats(ctx context.Context, region string, playerName string, since time.Time) (*SmallScaleStats, error) {
+	var stats []SmallScaleStats
+	err := p.db.WithContext(ctx).Raw(
+		fmt.Sprintf(smallScaleQuery, "AND killer_name = @name", "AND victim_name = @name"),
+		map[string]interface{}{"region": region, "since": since, "name": playerName},
+	).Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(stats) == 0 {
+		return &SmallScaleStats{PlayerName: playerName}, nil
+	}
+	return &stats[0], nil
+}
+
+// GetTopSmallScalePlayers ranks players by kills in the bracket, then by fewest
+// deaths in it.
+func (p *Postgres) GetTopSmallScalePlayers(ctx context.Context, region string, bracket string, since time.Time, limit int, offset int) ([]SmallScaleStats, error) {
+	order := "solo_kills DESC, solo_deaths ASC"
+	if bracket == SmallScaleSmall {
+		order = "small_kills DESC, small_deaths ASC"
+	}
+
+	var stats []SmallScaleStats
+	err := p.db.WithContext(ctx).Raw(
+		fmt.Sprintf(smallScaleQuery, "", "")+`
+ORDER BY `+order+`, player_name
+LIMIT @limit OFFSET @offset`,
+		map[string]interface{}{"region": region, "since": since, "limit": limit, "offset": offset},
+	).Scan(&stats).Error
+
+	return stats, err
+}
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"time"
+)
+
+// WeaponMetaFilter narrows weapon stats to battles of a size and players in an IP
+// bracket. Zero maximums mean no upper bound.
+type WeaponMetaFilter struct {
+	Since      time.Time
+	MinPlayers int
+	MaxPlayers int
+	MinIP      int
+	MaxIP      int
+}
+
+// WeaponMetaRow totals one item id across player battles. Damage, heal and IP are
+// sums over the rows where they're known, with counts so averages can be combined
+// across ids.
+type WeaponMetaRow struct {
+	Weapon      string `gorm:"column:weapon"`
+	Usage       int64  `gorm:"column:usage"`
+	Kills       int64  `gorm:"column:kills"`
+	Deaths      int64  `gorm:"column:deaths"`
+	Damage      int64  `gorm:"column:damage"`
+	DamageCount int64  `gorm:"column:damage_count"`
+	Heal        int64  `gorm:"column:heal"`
+	HealCount   int64  `gorm:"column:heal_count"`
+	IP          int64  `gorm:"column:ip"`
+	IPCount     int64  `gorm:"column:ip_count"`
+}
+
+func (p *Postgres) GetWeaponMeta(ctx context.Context, region string, filter WeaponMetaFilter) ([]WeaponMetaRow, error) {
+	query := p.db.WithContext(ctx).
+		Table("battle_player_stats bps").
+		Select(`
+    bps.weapon,
+    COUNT(*) AS usage,
+    COALESCE(SUM(bps.kills), 0) AS kills,
+    COALESCE(SUM(bps.deaths), 0) AS deaths,
+    COALESCE(SUM(bps.damage), 0) AS damage,
+    COUNT(bps.damage) AS damage_count,
+    COALESCE(SUM(bps.heal), 0) AS heal,
+    COUNT(bps.heal) AS heal_count,
+    COALESCE(SUM(bps.ip), 0) AS ip,
+    COUNT(bps.ip) AS ip_count`).
+		Joins("JOIN battle_summary bs ON bs.region = bps.region AND bs.battle_id = bps.battle_id").
+		Where("bps.region = ? AND bps.start_time >= ? AND bps.weapon IS NOT NULL AND bps.weapon <> ''", region, filter.Since).
+		Where("bs.total_players >= ?", filter.MinPlayers)
+
+	if filter.MaxPlayers > 0 {
+		query = query.Where("bs.total_players <= ?", filter.MaxPlayers)
+	}
+	if filter.MinIP > 0 {
+		query = query.Where("bps.ip >= ?", filter.MinIP)
+	}
+	if filter.MaxIP > 0 {
+		query = query.Where("bps.ip <= ?", filter.MaxIP)
+	}
+
+	var rows []WeaponMetaRow
+	err := query.Group("bps.weapon").Scan(&rows).Error
+	return rows, err
+}
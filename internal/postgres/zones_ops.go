@@ -0,0 +1,20 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm/clause"
+)
+
+// UpsertZones stores the zone catalogue, updating the name and type of clusters that
+// are already known. Zones missing from the list are left alone.
+func (p *Postgres) UpsertZones(ctx context.Context, zones []Zone) error {
+	if len(zones) == 0 {
+		return nil
+	}
+
+	return p.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cluster_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "type"}),
+	}).CreateInBatches(zones, 500).Error
+}
@@ -155,6 +155,61 @@ func (c *Client) FetchEvents(region string, limit int, offset int) ([]Event, err
 	return events, nil
 }
 
+// FetchPlayerDeaths returns a player's most recent deaths, newest first. It is called
+// while serving API requests, so waiting on the rate limiter stops with ctx.
+func (c *Client) FetchPlayerDeaths(ctx context.Context, region string, playerID string, limit int) ([]Event, error) {
+	baseUrl, err := regionToBaseURL(region)
+	if err != nil {
+		return nil, err
+	}
+
+	if limiter := getRegionLimiter(region); limiter != nil {
+		if err := limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	u, err := url.Parse(fmt.Sprintf("%s/api/gameinfo/players/%s/deaths", baseUrl, playerID))
+	if err != nil {
+		return nil, err
+	}
+
+	q := u.Query()
+	q.Set("limit", fmt.Sprintf("%d", limit))
+	q.Set("offset", "0")
+	q.Set("guid", generateRandomGUID())
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", c.userAgent)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var events []Event
+	if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
+		return nil, err
+	}
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
 func (c *Client) FetchBattles(region string, offset, limit int) (BattlesResponse, error) {
 	baseUrl, err := regionToBaseURL(region)
 	if err != nil {
@@ -380,7 +435,7 @@ type Event struct {
 type Participant struct {
 	AverageItemPower     float64                `json:"AverageItemPower,omitempty"`
 	Equipment            map[string]*EquipmentItem `json:"Equipment,omitempty"`
-	Inventory            []interface{}          `json:"Inventory,omitempty"`
+	Inventory            []*EquipmentItem       `json:"Inventory,omitempty"`
 	Name                 string                 `json:"Name"`
 	ID                   string                 `json:"Id"`
 	GuildName            string                 `json:"GuildName"`
@@ -0,0 +1,181 @@
+package battle_poller
+
+import (
+	"albionstats/internal/items"
+	"albionstats/internal/postgres"
+	"albionstats/internal/tasks"
+	"albionstats/internal/util"
+)
+
+type awardCandidate struct {
+	name         string
+	guildName    string
+	allianceName string
+	side         int16
+	role         string
+	perf         util.BattlePerformance
+	score        float64
+}
+
+// processBattleAwards scores every player and picks the MVP, top healer, top damage
+// and top tank of the whole battle and of each side.
+func (p *BattlePoller) processBattleAwards(events []tasks.Event, playerStats []postgres.BattlePlayerStats, sides []postgres.BattleSideAssignment) []postgres.BattleAward {
+	battleId := events[0].BattleID
+	startTime := events[0].TimeStamp
+
+	candidates := make(map[string]*awardCandidate)
+	candidateOf := func(participant tasks.Participant) *awardCandidate {
+		c, ok := candidates[participant.Name]
+		if !ok {
+			c = &awardCandidate{name: participant.Name}
+			candidates[participant.Name] = c
+		}
+		if participant.GuildName != "" {
+			c.guildName = participant.GuildName
+			c.allianceName = participant.AllianceName
+		}
+		return c
+	}
+
+	for _, event := range events {
+		if event.TimeStamp.Before(startTime) {
+			startTime = event.TimeStamp
+		}
+
+		killer := candidateOf(event.Killer)
+		killer.perf.Kills++
+		killer.perf.KillFame += event.TotalVictimKillFame
+		candidateOf(event.Victim).perf.Deaths++
+		for _, participant := range event.Participants {
+			candidateOf(participant)
+		}
+	}
+
+	var totalIP, ipCount int64
+	for _, stat := range playerStats {
+		c, ok := candidates[stat.PlayerName]
+		if !ok {
+			continue
+		}
+		if stat.Assists != nil {
+			c.perf.Assists = *stat.Assists
+		}
+		if stat.Damage != nil {
+			c.perf.Damage = *stat.Damage
+		}
+		if stat.Heal != nil {
+			c.perf.Healing = *stat.Heal
+		}
+		if stat.IP != nil && *stat.IP > 0 {
+			c.perf.IP = *stat.IP
+			totalIP += int64(*stat.IP)
+			ipCount++
+		}
+		if stat.Weapon != nil {
+			c.role = p.items.Role(*stat.Weapon)
+		}
+	}
+
+	var averageIP float64
+	if ipCount > 0 {
+		averageIP = float64(totalIP) / float64(ipCount)
+	}
+
+	guildSides := make(map[string]int16)
+	allianceSides := make(map[string]int16)
+	for _, side := range sides {
+		switch side.EntityType {
+		case postgres.SideEntityGuild:
+			guildSides[side.EntityName] = side.Side
+		case postgres.SideEntityAlliance:
+			allianceSides[side.EntityName] = side.Side
+		}
+	}
+
+	for _, c := range candidates {
+		c.score = p.mvpWeights.Score(c.perf, averageIP)
+		if side, ok := guildSides[c.guildName]; ok && c.guildName != "" {
+			c.side = side
+		} else if side, ok := allianceSides[c.allianceName]; ok && c.allianceName != "" {
+			c.side = side
+		}
+	}
+
+	awards := make([]postgres.BattleAward, 0)
+	for _, side := range []int16{0, 1, 2} {
+		var mvp, healer, damage, tank *awardCandidate
+		for _, c := range candidates {
+			if side != 0 && c.side != side {
+				continue
+			}
+			if c.score > 0 && better(c, mvp, c.score, scoreOf(mvp)) {
+				mvp = c
+			}
+			if c.perf.Healing > 0 && better(c, healer, float64(c.perf.Healing), healingOf(healer)) {
+				healer = c
+			}
+			if c.perf.Damage > 0 && better(c, damage, float64(c.perf.Damage), damageOf(damage)) {
+				damage = c
+			}
+			if c.role == items.RoleTank && c.score > 0 && better(c, tank, c.score, scoreOf(tank)) {
+				tank = c
+			}
+		}
+
+		add := func(award string, c *awardCandidate) {
+			if c == nil {
+				return
+			}
+			awards = append(awards, postgres.BattleAward{
+				Region:       postgres.Region(p.region),
+				BattleID:     battleId,
+				Side:         side,
+				Award:        award,
+				PlayerName:   c.name,
+				GuildName:    util.NullableString(c.guildName),
+				AllianceName: util.NullableString(c.allianceName),
+				Score:        c.score,
+				StartTime:    startTime,
+			})
+		}
+		add(postgres.AwardMVP, mvp)
+		add(postgres.AwardHealer, healer)
+		add(postgres.AwardDamage, damage)
+		add(postgres.AwardTank, tank)
+	}
+
+	return awards
+}
+
+// better reports whether c beats the current pick, breaking ties by name so reruns
+// pick the same player.
+func better(c, current *awardCandidate, value, currentValue float64) bool {
+	if current == nil {
+		return true
+	}
+	if value != currentValue {
+		return value > currentValue
+	}
+	return c.name < current.name
+}
+
+func scoreOf(c *awardCandidate) float64 {
+	if c == nil {
+		return 0
+	}
+	return c.score
+}
+
+func healingOf(c *awardCandidate) float64 {
+	if c == nil {
+		return 0
+	}
+	return float64(c.perf.Healing)
+}
+
+func damageOf(c *awardCandidate) float64 {
+	if c == nil {
+		return 0
+	}
+	return float64(c.perf.Damage)
+}
@@ -0,0 +1,99 @@
+package battle_poller
+
+import (
+	"albionstats/internal/postgres"
+	"albionstats/internal/tasks"
+	"albionstats/internal/util"
+	"testing"
+)
+
+type awardKey struct {
+	side  int16
+	award string
+}
+
+func member(name, guild, alliance string) tasks.Participant {
+	return tasks.Participant{Name: name, GuildName: guild, AllianceName: alliance}
+}
+
+func stat(name string, weapon string, heal, damage int64) postgres.BattlePlayerStats {
+	s := postgres.BattlePlayerStats{PlayerName: name, Heal: &heal, Damage: &damage}
+	if weapon != "" {
+		s.Weapon = &weapon
+	}
+	return s
+}
+
+func TestProcessBattleAwards(t *testing.T) {
+	a1, a2, a3 := member("a1", "GA", "A"), member("a2", "GA", "A"), member("a3", "GA", "A")
+	b1, b2 := member("b1", "GB", "B"), member("b2", "GB", "B")
+	sides := []postgres.BattleSideAssignment{
+		{EntityType: postgres.SideEntityAlliance, EntityName: "A", Side: 1},
+		{EntityType: postgres.SideEntityAlliance, EntityName: "B", Side: 2},
+	}
+
+	tests := []struct {
+		name   string
+		events []tasks.Event
+		stats  []postgres.BattlePlayerStats
+		want   map[awardKey]string
+	}{
+		{
+			name:   "ties go to the lower name",
+			events: []tasks.Event{kill(a2, b1, 100), kill(a1, b2, 100)},
+			stats:  []postgres.BattlePlayerStats{stat("a1", "", 500, 0), stat("a2", "", 500, 0)},
+			want: map[awardKey]string{
+				{0, postgres.AwardMVP}:    "a1",
+				{0, postgres.AwardHealer}: "a1",
+				{1, postgres.AwardMVP}:    "a1",
+				{1, postgres.AwardHealer}: "a1",
+			},
+		},
+		{
+			name:   "awards are picked per side",
+			events: []tasks.Event{kill(a1, b1, 100), kill(a1, b2, 100), kill(b1, a2, 100)},
+			stats:  []postgres.BattlePlayerStats{stat("a2", "", 0, 300), stat("b2", "", 0, 900)},
+			want: map[awardKey]string{
+				{0, postgres.AwardMVP}:    "a1",
+				{0, postgres.AwardDamage}: "b2",
+				{1, postgres.AwardMVP}:    "a1",
+				{1, postgres.AwardDamage}: "a2",
+				{2, postgres.AwardMVP}:    "b1",
+				{2, postgres.AwardDamage}: "b2",
+			},
+		},
+		{
+			name:   "tanks need a positive score",
+			events: []tasks.Event{kill(b1, a3, 100), kill(a1, b1, 100)},
+			stats:  []postgres.BattlePlayerStats{stat("a3", "T8_MAIN_MACE", 0, 0), stat("b1", "T8_MAIN_MACE", 0, 0)},
+			want: map[awardKey]string{
+				{0, postgres.AwardMVP}:  "a1",
+				{0, postgres.AwardTank}: "b1",
+				{1, postgres.AwardMVP}:  "a1",
+				{2, postgres.AwardMVP}:  "b1",
+				{2, postgres.AwardTank}: "b1",
+			},
+		},
+	}
+
+	p := &BattlePoller{region: string(postgres.RegionEurope), mvpWeights: util.MVPWeights{Kills: 10, Deaths: 5}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Run a few times so map iteration order can't hide an unstable pick
+			for run := 0; run < 5; run++ {
+				got := make(map[awardKey]string)
+				for _, award := range p.processBattleAwards(tt.events, tt.stats, sides) {
+					got[awardKey{award.Side, award.Award}] = award.PlayerName
+				}
+				if len(got) != len(tt.want) {
+					t.Fatalf("awards %v, want %v", got, tt.want)
+				}
+				for key, name := range tt.want {
+					if got[key] != name {
+						t.Errorf("side %d %s: %q, want %q", key.side, key.award, got[key], name)
+					}
+				}
+			}
+		})
+	}
+}
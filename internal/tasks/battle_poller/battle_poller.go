@@ -1,8 +1,11 @@
 package battle_poller
 
 import (
+	"albionstats/internal/items"
 	"albionstats/internal/postgres"
 	"albionstats/internal/tasks"
+	"albionstats/internal/tasks/legendary_souls"
+	"albionstats/internal/util"
 	"log/slog"
 	"time"
 )
@@ -12,21 +15,32 @@ type Config struct {
 	Postgres  *postgres.Postgres
 	Logger    *slog.Logger
 	Region    string
+	// Prices values victims' equipment and inventory. Nil is allowed.
+	Prices *items.PriceTable
+	// Items classifies weapons for the top tank award. Nil falls back to weapon lines.
+	Items      *items.Catalogue
+	MVPWeights util.MVPWeights
 }
 
 type BattlePoller struct {
-	apiClient *tasks.Client
-	postgres  *postgres.Postgres
-	log       *slog.Logger
-	region    string
+	apiClient  *tasks.Client
+	postgres   *postgres.Postgres
+	log        *slog.Logger
+	region     string
+	prices     *items.PriceTable
+	items      *items.Catalogue
+	mvpWeights util.MVPWeights
 }
 
 func NewBattlePoller(cfg Config) *BattlePoller {
 	return &BattlePoller{
-		apiClient: cfg.APIClient,
-		postgres:  cfg.Postgres,
-		log:       cfg.Logger.With("component", "battle_poller", "region", cfg.Region),
-		region:    cfg.Region,
+		apiClient:  cfg.APIClient,
+		postgres:   cfg.Postgres,
+		log:        cfg.Logger.With("component", "battle_poller", "region", cfg.Region),
+		region:     cfg.Region,
+		prices:     cfg.Prices,
+		items:      cfg.Items,
+		mvpWeights: cfg.MVPWeights,
 	}
 }
 
@@ -76,6 +90,12 @@ func (p *BattlePoller) runBatch() {
 		guildStats := p.processBattleGuildStats(events)
 		playerStats := p.processPlayerStats(events)
 		kills := p.processBattleKills(events)
+		participants := p.processKillParticipants(events)
+		battleLoadouts := p.processBattleLoadouts(events)
+		killLoadouts := p.processKillLoadouts(events)
+		sides, winningSide := p.processBattleSides(events)
+		awards := p.processBattleAwards(events, playerStats, sides)
+		souls, soulSightings := legendary_souls.Collect(p.region, events)
 
 		if err := p.postgres.UpdateBattleAllianceStats(allianceStats); err != nil {
 			p.log.Error("update battle alliance stats failed", "err", err)
@@ -97,6 +117,43 @@ func (p *BattlePoller) runBatch() {
 			continue
 		}
 
+		if err := p.postgres.InsertBattleKillParticipants(participants); err != nil {
+			p.log.Error("insert battle kill participants failed", "err", err)
+			continue
+		}
+
+		if err := p.postgres.ReplaceBattleLoadouts(postgres.Region(p.region), queue.BattleID, battleLoadouts); err != nil {
+			p.log.Error("replace battle loadouts failed", "err", err)
+			continue
+		}
+
+		if err := p.postgres.InsertKillLoadouts(killLoadouts); err != nil {
+			p.log.Error("insert kill loadouts failed", "err", err)
+			continue
+		}
+
+		if err := p.postgres.UpsertLegendarySouls(souls, soulSightings); err != nil {
+			p.log.Error("upsert legendary souls failed", "err", err)
+			continue
+		}
+
+		if err := p.postgres.ReplaceBattleSides(postgres.Region(p.region), queue.BattleID, sides, winningSide); err != nil {
+			p.log.Error("replace battle sides failed", "err", err)
+			continue
+		}
+
+		if err := p.postgres.ReplaceBattleAwards(postgres.Region(p.region), queue.BattleID, awards); err != nil {
+			p.log.Error("replace battle awards failed", "err", err)
+			continue
+		}
+
+		if killArea := events[0].KillArea; killArea != "" {
+			if err := p.postgres.UpdateBattleSummaryKillArea(postgres.Region(p.region), queue.BattleID, killArea); err != nil {
+				p.log.Error("update battle summary kill area failed", "err", err)
+				continue
+			}
+		}
+
 		if err := p.postgres.MarkBattleQueueProcessed(postgres.Region(p.region), queue.BattleID); err != nil {
 			p.log.Error("mark battle queue processed failed", "err", err)
 			continue
@@ -106,12 +163,17 @@ func (p *BattlePoller) runBatch() {
 			"alliance_stats", len(allianceStats),
 			"guild_stats", len(guildStats),
 			"player_stats", len(playerStats),
-			"kills", len(kills))
+			"kills", len(kills),
+			"kill_participants", len(participants),
+			"loadout_items", len(battleLoadouts),
+			"sides", len(sides),
+			"awards", len(awards))
 	}
 }
 
 func (p *BattlePoller) fetchBattleEvents(battleId int64) ([]tasks.Event, error) {
 	var allEvents []tasks.Event
+	seen := make(map[int64]struct{})
 	offset := 0
 	limit := 51
 
@@ -121,7 +183,14 @@ func (p *BattlePoller) fetchBattleEvents(battleId int64) ([]tasks.Event, error)
 			return nil, err
 		}
 
-		allEvents = append(allEvents, events...)
+		// Pages overlap by one event, so skip any event we've already collected
+		for _, event := range events {
+			if _, ok := seen[event.EventID]; ok {
+				continue
+			}
+			seen[event.EventID] = struct{}{}
+			allEvents = append(allEvents, event)
+		}
 
 		// If we got fewer events than the limit, we've reached the end
 		if len(events) < limit {
@@ -222,6 +291,9 @@ func (p *BattlePoller) processPlayerStats(events []tasks.Event) []postgres.Battl
 	playerWeapon := make(map[string]string)
 	playerDamage := make(map[string]int64)
 	playerHeal := make(map[string]int64)
+	playerAssists := make(map[string]int32)
+	playerSilverLost := make(map[string]int64)
+	streaks := processKillStreaks(events)
 
 	// kills
 	for _, event := range events {
@@ -241,6 +313,7 @@ func (p *BattlePoller) processPlayerStats(events []tasks.Event) []postgres.Battl
 	for _, event := range events {
 		all[event.Victim.Name] = true
 		playerDeathFame[event.Victim.Name] += event.TotalVictimKillFame
+		playerSilverLost[event.Victim.Name] += event.Victim.SilverLost(p.prices)
 
 		if _, ok := playerIp[event.Victim.Name]; !ok {
 			playerIp[event.Victim.Name] = event.Victim.AverageItemPower
@@ -255,11 +328,17 @@ func (p *BattlePoller) processPlayerStats(events []tasks.Event) []postgres.Battl
 
 	// participants
 	for _, event := range events {
+		assisted := make(map[string]bool)
 		for _, p := range event.Participants {
 			all[p.Name] = true
 			playerDamage[p.Name] += int64(p.DamageDone)
 			playerHeal[p.Name] += int64(p.SupportHealingDone)
 
+			if p.Name != event.Killer.Name && !assisted[p.Name] {
+				assisted[p.Name] = true
+				playerAssists[p.Name]++
+			}
+
 			if _, ok := playerIp[p.Name]; !ok {
 				playerIp[p.Name] = p.AverageItemPower
 			}
@@ -318,6 +397,22 @@ func (p *BattlePoller) processPlayerStats(events []tasks.Event) []postgres.Battl
 			stat.Heal = &v
 		}
 
+		assists := playerAssists[name]
+		stat.Assists = &assists
+
+		if v, ok := playerSilverLost[name]; ok {
+			v := v
+			stat.SilverLost = &v
+		}
+
+		streak, ok := streaks[name]
+		if !ok {
+			streak = &killStreak{}
+		}
+		stat.KillStreak = &streak.best
+		stat.MultiKills = &streak.multiKills
+		stat.BestMultiKill = &streak.bestMultiKill
+
 		playerStats = append(playerStats, stat)
 	}
 
@@ -342,17 +437,61 @@ func (p *BattlePoller) processBattleKills(events []tasks.Event) []postgres.Battl
 		}
 
 		playerStats = append(playerStats, postgres.BattleKills{
-			Region:       postgres.Region(p.region),
-			BattleID:     event.BattleID,
-			TS:           event.TimeStamp,
-			KillerName:   event.Killer.Name,
-			KillerIP:     int32(event.Killer.AverageItemPower),
-			KillerWeapon: killerWeapon,
-			VictimName:   event.Victim.Name,
-			VictimIP:     int32(event.Victim.AverageItemPower),
-			VictimWeapon: victimWeapon,
-			Fame:         event.TotalVictimKillFame,
+			Region:         postgres.Region(p.region),
+			EventID:        event.EventID,
+			BattleID:       event.BattleID,
+			TS:             event.TimeStamp,
+			KillerID:       util.NullableString(event.Killer.ID),
+			KillerName:     event.Killer.Name,
+			KillerGuild:    util.NullableString(event.Killer.GuildName),
+			KillerAlliance: util.NullableString(event.Killer.AllianceName),
+			KillerIP:       int32(event.Killer.AverageItemPower),
+			KillerWeapon:   killerWeapon,
+			VictimID:       util.NullableString(event.Victim.ID),
+			VictimName:     event.Victim.Name,
+			VictimGuild:    util.NullableString(event.Victim.GuildName),
+			VictimAlliance: util.NullableString(event.Victim.AllianceName),
+			VictimIP:       int32(event.Victim.AverageItemPower),
+			VictimWeapon:   victimWeapon,
+			Fame:           event.TotalVictimKillFame,
+			SilverLost:     event.Victim.SilverLost(p.prices),
 		})
 	}
 	return playerStats
 }
+
+func (p *BattlePoller) processKillParticipants(events []tasks.Event) []postgres.BattleKillParticipant {
+	participants := make([]postgres.BattleKillParticipant, 0)
+	for _, event := range events {
+		seen := make(map[string]bool)
+		for _, participant := range event.Participants {
+			if seen[participant.Name] {
+				continue
+			}
+			seen[participant.Name] = true
+
+			var weapon *string
+			if eq := participant.Equipment; eq != nil {
+				if mh, ok := eq["MainHand"]; ok && mh != nil {
+					weapon = util.NullableString(mh.Type)
+				}
+			}
+
+			participants = append(participants, postgres.BattleKillParticipant{
+				Region:       postgres.Region(p.region),
+				EventID:      event.EventID,
+				PlayerName:   participant.Name,
+				BattleID:     event.BattleID,
+				TS:           event.TimeStamp,
+				PlayerID:     util.NullableString(participant.ID),
+				GuildName:    util.NullableString(participant.GuildName),
+				AllianceName: util.NullableString(participant.AllianceName),
+				IP:           int32(participant.AverageItemPower),
+				Weapon:       weapon,
+				Damage:       int64(participant.DamageDone),
+				Heal:         int64(participant.SupportHealingDone),
+			})
+		}
+	}
+	return participants
+}
@@ -0,0 +1,99 @@
+package battle_poller
+
+import (
+	"albionstats/internal/postgres"
+	"albionstats/internal/tasks"
+	"sort"
+	"time"
+)
+
+// loadout is a player's equipment as seen in one event.
+type loadout struct {
+	ts        time.Time
+	equipment map[string]*tasks.EquipmentItem
+}
+
+// equippedSlots returns the filled slots of an equipment map in a stable order.
+func equippedSlots(equipment map[string]*tasks.EquipmentItem) []string {
+	slots := make([]string, 0, len(equipment))
+	for slot, item := range equipment {
+		if item != nil && item.Type != "" {
+			slots = append(slots, slot)
+		}
+	}
+	sort.Strings(slots)
+	return slots
+}
+
+// processBattleLoadouts keeps the latest loadout each player was seen with across the
+// battle's killers, victims, participants and group members.
+func (p *BattlePoller) processBattleLoadouts(events []tasks.Event) []postgres.BattleLoadoutItem {
+	battleId := events[0].BattleID
+
+	latest := make(map[string]loadout)
+	see := func(participant tasks.Participant, ts time.Time) {
+		if len(equippedSlots(participant.Equipment)) == 0 {
+			return
+		}
+		if seen, ok := latest[participant.Name]; ok && seen.ts.After(ts) {
+			return
+		}
+		latest[participant.Name] = loadout{ts: ts, equipment: participant.Equipment}
+	}
+
+	for _, event := range events {
+		see(event.Killer, event.TimeStamp)
+		see(event.Victim, event.TimeStamp)
+		for _, participant := range event.Participants {
+			see(participant, event.TimeStamp)
+		}
+		for _, member := range event.GroupMembers {
+			see(member, event.TimeStamp)
+		}
+	}
+
+	items := make([]postgres.BattleLoadoutItem, 0)
+	for name, l := range latest {
+		for _, slot := range equippedSlots(l.equipment) {
+			item := l.equipment[slot]
+			items = append(items, postgres.BattleLoadoutItem{
+				Region:     postgres.Region(p.region),
+				BattleID:   battleId,
+				PlayerName: name,
+				Slot:       slot,
+				ItemType:   item.Type,
+				Quality:    int16(item.Quality),
+				Count:      item.Count,
+				TS:         l.ts,
+			})
+		}
+	}
+	return items
+}
+
+func (p *BattlePoller) processKillLoadouts(events []tasks.Event) []postgres.KillLoadoutItem {
+	items := make([]postgres.KillLoadoutItem, 0)
+	for _, event := range events {
+		for role, participant := range map[string]tasks.Participant{
+			postgres.LoadoutRoleKiller: event.Killer,
+			postgres.LoadoutRoleVictim: event.Victim,
+		} {
+			for _, slot := range equippedSlots(participant.Equipment) {
+				item := participant.Equipment[slot]
+				items = append(items, postgres.KillLoadoutItem{
+					Region:     postgres.Region(p.region),
+					EventID:    event.EventID,
+					Role:       role,
+					Slot:       slot,
+					BattleID:   event.BattleID,
+					PlayerName: participant.Name,
+					ItemType:   item.Type,
+					Quality:    int16(item.Quality),
+					Count:      item.Count,
+					TS:         event.TimeStamp,
+				})
+			}
+		}
+	}
+	return items
+}
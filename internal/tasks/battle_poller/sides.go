@@ -0,0 +1,248 @@
+package battle_poller
+
+import (
+	"albionstats/internal/postgres"
+	"albionstats/internal/tasks"
+	"albionstats/internal/util"
+	"sort"
+	"time"
+)
+
+// sideUnit is the group a player fights with: their alliance, or their guild if they
+// aren't in an alliance.
+type sideUnit struct {
+	entityType string
+	name       string
+}
+
+func unitOf(p tasks.Participant) (sideUnit, bool) {
+	if p.AllianceName != "" {
+		return sideUnit{postgres.SideEntityAlliance, p.AllianceName}, true
+	}
+	if p.GuildName != "" {
+		return sideUnit{postgres.SideEntityGuild, p.GuildName}, true
+	}
+	return sideUnit{}, false
+}
+
+// relations counts, for each pair of units, how often they fought each other and how
+// often they fought together (killer, assisting participants and the killer's group).
+type relations struct {
+	involvement map[sideUnit]int
+	enemy       map[sideUnit]map[sideUnit]int
+	ally        map[sideUnit]map[sideUnit]int
+}
+
+func newRelations() *relations {
+	return &relations{
+		involvement: make(map[sideUnit]int),
+		enemy:       make(map[sideUnit]map[sideUnit]int),
+		ally:        make(map[sideUnit]map[sideUnit]int),
+	}
+}
+
+func addWeight(m map[sideUnit]map[sideUnit]int, a, b sideUnit) {
+	if m[a] == nil {
+		m[a] = make(map[sideUnit]int)
+	}
+	if m[b] == nil {
+		m[b] = make(map[sideUnit]int)
+	}
+	m[a][b]++
+	m[b][a]++
+}
+
+func buildRelations(events []tasks.Event) *relations {
+	r := newRelations()
+	for _, event := range events {
+		killer, killerOk := unitOf(event.Killer)
+		victim, victimOk := unitOf(event.Victim)
+		if killerOk {
+			r.involvement[killer]++
+		}
+		if victimOk {
+			r.involvement[victim]++
+		}
+		if killerOk && victimOk && killer != victim {
+			addWeight(r.enemy, killer, victim)
+		}
+
+		attackers := make(map[sideUnit]struct{})
+		if killerOk {
+			attackers[killer] = struct{}{}
+		}
+		for _, p := range event.Participants {
+			if u, ok := unitOf(p); ok && u != victim {
+				attackers[u] = struct{}{}
+			}
+		}
+		for _, m := range event.GroupMembers {
+			if u, ok := unitOf(m); ok && u != victim {
+				attackers[u] = struct{}{}
+			}
+		}
+
+		units := make([]sideUnit, 0, len(attackers))
+		for u := range attackers {
+			units = append(units, u)
+		}
+		for i := range units {
+			for j := i + 1; j < len(units); j++ {
+				addWeight(r.ally, units[i], units[j])
+			}
+		}
+	}
+	return r
+}
+
+// inferSides splits units into two sides so that allies share a side and enemies
+// don't. Units are placed greedily from most to least involved, then moved between
+// sides until no move improves the split. Units with no relation to any placed unit
+// are left out.
+func inferSides(r *relations) map[sideUnit]int16 {
+	units := make([]sideUnit, 0, len(r.involvement))
+	for u := range r.involvement {
+		units = append(units, u)
+	}
+	sort.Slice(units, func(i, j int) bool {
+		if r.involvement[units[i]] != r.involvement[units[j]] {
+			return r.involvement[units[i]] > r.involvement[units[j]]
+		}
+		return units[i].name < units[j].name
+	})
+
+	sides := make(map[sideUnit]int16)
+	score := func(u sideUnit, side int16) int {
+		total := 0
+		for other, w := range r.ally[u] {
+			if other != u && sides[other] == side {
+				total += w
+			}
+		}
+		for other, w := range r.enemy[u] {
+			if other != u && sides[other] == side {
+				total -= w
+			}
+		}
+		return total
+	}
+
+	// Placing a unit can connect earlier ones, so keep sweeping until nothing changes
+	for placed := true; placed; {
+		placed = false
+		for _, u := range units {
+			if _, ok := sides[u]; ok {
+				continue
+			}
+			if len(sides) == 0 {
+				sides[u] = 1
+				placed = true
+				continue
+			}
+			s1, s2 := score(u, 1), score(u, 2)
+			if s1 == 0 && s2 == 0 {
+				continue
+			}
+			if s1 >= s2 {
+				sides[u] = 1
+			} else {
+				sides[u] = 2
+			}
+			placed = true
+		}
+	}
+
+	for pass := 0; pass < 10; pass++ {
+		moved := false
+		for _, u := range units {
+			side, ok := sides[u]
+			if !ok {
+				continue
+			}
+			other := 3 - side
+			if score(u, other) > score(u, side) {
+				sides[u] = other
+				moved = true
+			}
+		}
+		if !moved {
+			break
+		}
+	}
+
+	return sides
+}
+
+// winningSide totals the kill fame each side took from the other.
+func winningSide(events []tasks.Event, sides map[sideUnit]int16) *int16 {
+	var fame [3]int64
+	for _, event := range events {
+		killer, ok := unitOf(event.Killer)
+		if !ok {
+			continue
+		}
+		victim, ok := unitOf(event.Victim)
+		if !ok || sides[killer] == sides[victim] {
+			continue
+		}
+		fame[sides[killer]] += event.TotalVictimKillFame
+	}
+	return util.WinningSide(fame[1], fame[2])
+}
+
+func (p *BattlePoller) processBattleSides(events []tasks.Event) ([]postgres.BattleSideAssignment, *int16) {
+	battleId := events[0].BattleID
+	startTime := events[0].TimeStamp
+	for _, event := range events {
+		if event.TimeStamp.Before(startTime) {
+			startTime = event.TimeStamp
+		}
+	}
+
+	sides := inferSides(buildRelations(events))
+
+	// Guilds take their alliance's side, or their own if they aren't in one
+	guildSides := make(map[string]int16)
+	addGuild := func(participant tasks.Participant) {
+		if participant.GuildName == "" {
+			return
+		}
+		if u, ok := unitOf(participant); ok {
+			if side, ok := sides[u]; ok {
+				guildSides[participant.GuildName] = side
+			}
+		}
+	}
+	for _, event := range events {
+		addGuild(event.Killer)
+		addGuild(event.Victim)
+		for _, participant := range event.Participants {
+			addGuild(participant)
+		}
+		for _, member := range event.GroupMembers {
+			addGuild(member)
+		}
+	}
+
+	assignments := make([]postgres.BattleSideAssignment, 0, len(sides)+len(guildSides))
+	add := func(entityType, name string, side int16, startTime time.Time) {
+		assignments = append(assignments, postgres.BattleSideAssignment{
+			Region:     postgres.Region(p.region),
+			BattleID:   battleId,
+			EntityType: entityType,
+			EntityName: name,
+			Side:       side,
+			StartTime:  startTime,
+		})
+	}
+	for u, side := range sides {
+		if u.entityType == postgres.SideEntityAlliance {
+			add(postgres.SideEntityAlliance, u.name, side, startTime)
+		}
+	}
+	for guild, side := range guildSides {
+		add(postgres.SideEntityGuild, guild, side, startTime)
+	}
+
+	return assignments, winningSide(events, sides)
+}
@@ -0,0 +1,124 @@
+package battle_poller
+
+import (
+	"albionstats/internal/postgres"
+	"albionstats/internal/tasks"
+	"testing"
+)
+
+func player(name, alliance string) tasks.Participant {
+	return tasks.Participant{Name: name, AllianceName: alliance}
+}
+
+func kill(killer, victim tasks.Participant, fame int64, assists ...tasks.Participant) tasks.Event {
+	return tasks.Event{
+		Killer:              killer,
+		Victim:              victim,
+		TotalVictimKillFame: fame,
+		Participants:        append([]tasks.Participant{killer}, assists...),
+	}
+}
+
+func alliance(name string) sideUnit {
+	return sideUnit{postgres.SideEntityAlliance, name}
+}
+
+func TestInferSides(t *testing.T) {
+	a, b, c, d := player("a", "A"), player("b", "B"), player("c", "C"), player("d", "D")
+	loner := tasks.Participant{Name: "loner"}
+
+	tests := []struct {
+		name   string
+		events []tasks.Event
+		// Units expected together on one side and the units expected on the other
+		together [][]sideUnit
+		apart    [][2]sideUnit
+	}{
+		{
+			name: "two alliances fighting",
+			events: []tasks.Event{
+				kill(a, b, 100),
+				kill(b, a, 100),
+			},
+			apart: [][2]sideUnit{{alliance("A"), alliance("B")}},
+		},
+		{
+			name: "assists put alliances on the same side",
+			events: []tasks.Event{
+				kill(a, b, 100, c),
+				kill(b, c, 100, d),
+				kill(a, d, 100),
+			},
+			together: [][]sideUnit{{alliance("A"), alliance("C")}, {alliance("B"), alliance("D")}},
+			apart:    [][2]sideUnit{{alliance("A"), alliance("B")}, {alliance("C"), alliance("D")}},
+		},
+		{
+			name: "more fights between two allies than help outweighs",
+			events: []tasks.Event{
+				kill(a, b, 100, c),
+				kill(a, c, 100),
+				kill(a, c, 100),
+				kill(c, a, 100),
+			},
+			apart: [][2]sideUnit{{alliance("A"), alliance("C")}},
+		},
+		{
+			name: "players without an alliance or guild have no unit",
+			events: []tasks.Event{
+				kill(a, b, 100),
+				kill(loner, a, 100),
+			},
+			apart: [][2]sideUnit{{alliance("A"), alliance("B")}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sides := inferSides(buildRelations(tt.events))
+			for _, group := range tt.together {
+				for _, u := range group[1:] {
+					if sides[u] == 0 || sides[u] != sides[group[0]] {
+						t.Errorf("%v on side %d, want side of %v (%d)", u, sides[u], group[0], sides[group[0]])
+					}
+				}
+			}
+			for _, pair := range tt.apart {
+				if sides[pair[0]] == 0 || sides[pair[1]] == 0 || sides[pair[0]] == sides[pair[1]] {
+					t.Errorf("%v on side %d and %v on side %d, want different sides", pair[0], sides[pair[0]], pair[1], sides[pair[1]])
+				}
+			}
+			for u, side := range sides {
+				if side != 1 && side != 2 {
+					t.Errorf("%v on side %d, want 1 or 2", u, side)
+				}
+			}
+		})
+	}
+}
+
+func TestWinningSideFromKills(t *testing.T) {
+	a, b, c := player("a", "A"), player("b", "B"), player("c", "C")
+	sides := map[sideUnit]int16{alliance("A"): 1, alliance("C"): 1, alliance("B"): 2}
+
+	tests := []struct {
+		name   string
+		events []tasks.Event
+		want   *int16
+	}{
+		{name: "no kills", events: nil, want: nil},
+		{name: "side one takes more fame", events: []tasks.Event{kill(a, b, 300), kill(b, a, 100)}, want: sidePtr(1)},
+		{name: "side two takes more fame", events: []tasks.Event{kill(a, b, 100), kill(b, c, 300)}, want: sidePtr(2)},
+		{name: "kills within a side don't count", events: []tasks.Event{kill(a, c, 1000), kill(b, a, 100)}, want: sidePtr(2)},
+		{name: "close fame is a draw", events: []tasks.Event{kill(a, b, 100), kill(b, a, 95)}, want: sidePtr(0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := winningSide(tt.events, sides)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("winningSide() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func sidePtr(s int16) *int16 { return &s }
@@ -0,0 +1,79 @@
+package battle_poller
+
+import (
+	"albionstats/internal/tasks"
+	"sort"
+	"time"
+)
+
+// multiKillWindow is the longest gap between two kills by the same player for them to
+// count towards one multi-kill.
+const multiKillWindow = 10 * time.Second
+
+type killStreak struct {
+	best          int32
+	multiKills    int32
+	bestMultiKill int32
+
+	current  int32
+	run      int32
+	lastKill time.Time
+}
+
+// endRun closes the player's current run of quick kills, counting it if it was a
+// multi-kill.
+func (k *killStreak) endRun() {
+	if k.run >= 2 {
+		k.multiKills++
+		if k.run > k.bestMultiKill {
+			k.bestMultiKill = k.run
+		}
+	}
+	k.run = 0
+}
+
+// processKillStreaks walks a battle's kills in time order. A streak is the kills a
+// player lands before their next death; a multi-kill is a run of kills each within
+// multiKillWindow of the one before, which also ends when the player dies.
+func processKillStreaks(events []tasks.Event) map[string]*killStreak {
+	ordered := make([]tasks.Event, len(events))
+	copy(ordered, events)
+	sort.Slice(ordered, func(i, j int) bool {
+		if !ordered[i].TimeStamp.Equal(ordered[j].TimeStamp) {
+			return ordered[i].TimeStamp.Before(ordered[j].TimeStamp)
+		}
+		return ordered[i].EventID < ordered[j].EventID
+	})
+
+	streaks := make(map[string]*killStreak)
+	streakOf := func(name string) *killStreak {
+		k, ok := streaks[name]
+		if !ok {
+			k = &killStreak{}
+			streaks[name] = k
+		}
+		return k
+	}
+
+	for _, event := range ordered {
+		killer := streakOf(event.Killer.Name)
+		killer.current++
+		if killer.current > killer.best {
+			killer.best = killer.current
+		}
+		if killer.run > 0 && event.TimeStamp.Sub(killer.lastKill) > multiKillWindow {
+			killer.endRun()
+		}
+		killer.run++
+		killer.lastKill = event.TimeStamp
+
+		victim := streakOf(event.Victim.Name)
+		victim.current = 0
+		victim.endRun()
+	}
+
+	for _, k := range streaks {
+		k.endRun()
+	}
+	return streaks
+}
@@ -0,0 +1,98 @@
+package battle_poller
+
+import (
+	"albionstats/internal/tasks"
+	"testing"
+	"time"
+)
+
+var streakStart = time.Date(2025, 1, 1, 20, 0, 0, 0, time.UTC)
+
+func timedKill(id int64, at time.Duration, killer, victim string) tasks.Event {
+	return tasks.Event{
+		EventID:   id,
+		TimeStamp: streakStart.Add(at),
+		Killer:    tasks.Participant{Name: killer},
+		Victim:    tasks.Participant{Name: victim},
+	}
+}
+
+func TestProcessKillStreaks(t *testing.T) {
+	type want struct {
+		best, multiKills, bestMultiKill int32
+	}
+
+	tests := []struct {
+		name   string
+		events []tasks.Event
+		want   map[string]want
+	}{
+		{
+			name: "kills exactly on the window edge are one run",
+			events: []tasks.Event{
+				timedKill(1, 0, "a", "x"),
+				timedKill(2, multiKillWindow, "a", "y"),
+				timedKill(3, 2*multiKillWindow, "a", "z"),
+			},
+			want: map[string]want{"a": {best: 3, multiKills: 1, bestMultiKill: 3}},
+		},
+		{
+			name: "a gap just past the window ends the run",
+			events: []tasks.Event{
+				timedKill(1, 0, "a", "x"),
+				timedKill(2, multiKillWindow, "a", "y"),
+				timedKill(3, 2*multiKillWindow+time.Nanosecond, "a", "z"),
+			},
+			want: map[string]want{"a": {best: 3, multiKills: 1, bestMultiKill: 2}},
+		},
+		{
+			name: "dying ends the run and resets the streak",
+			events: []tasks.Event{
+				timedKill(1, 0, "a", "x"),
+				timedKill(2, time.Second, "a", "y"),
+				timedKill(3, 2*time.Second, "b", "a"),
+				timedKill(4, 3*time.Second, "a", "z"),
+			},
+			want: map[string]want{
+				"a": {best: 2, multiKills: 1, bestMultiKill: 2},
+				"b": {best: 1},
+			},
+		},
+		{
+			name: "a single kill is not a multi-kill",
+			events: []tasks.Event{
+				timedKill(1, 0, "a", "x"),
+				timedKill(2, time.Minute, "a", "y"),
+			},
+			want: map[string]want{"a": {best: 2}},
+		},
+		{
+			name: "kills are ordered by time then event id",
+			events: []tasks.Event{
+				timedKill(4, time.Second, "a", "z"),
+				timedKill(3, time.Second, "b", "a"),
+				timedKill(1, 0, "a", "x"),
+			},
+			want: map[string]want{
+				"a": {best: 1},
+				"b": {best: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			streaks := processKillStreaks(tt.events)
+			for name, w := range tt.want {
+				k, ok := streaks[name]
+				if !ok {
+					t.Fatalf("no streak for %s", name)
+				}
+				got := want{k.best, k.multiKills, k.bestMultiKill}
+				if got != w {
+					t.Errorf("%s: %+v, want %+v", name, got, w)
+				}
+			}
+		})
+	}
+}
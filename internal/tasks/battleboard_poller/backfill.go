@@ -0,0 +1,72 @@
+package battleboard_poller
+
+import (
+	"albionstats/internal/postgres"
+	"albionstats/internal/tasks"
+	"context"
+	"fmt"
+	"time"
+)
+
+// Backfill walks the battleboard from the newest battle back to since, storing and
+// queueing any battles that are missing. Progress is checkpointed after every page,
+// so an interrupted backfill resumes where it left off when run again with the same since.
+func (p *BattleboardPoller) Backfill(ctx context.Context, since time.Time) error {
+	checkpoint, err := p.postgres.GetBattleboardBackfill(postgres.Region(p.region), since)
+	if err != nil {
+		return fmt.Errorf("get backfill checkpoint: %w", err)
+	}
+
+	if checkpoint.Completed {
+		p.log.Info("battleboard backfill already completed", "since", since, "battles", checkpoint.Battles)
+		return nil
+	}
+
+	p.log.Info("battleboard backfill started", "since", since, "offset", checkpoint.NextOffset, "page_size", p.pageSize)
+
+	for !checkpoint.Completed {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		battles, err := p.fetchBattlesWithRetry(p.region, checkpoint.NextOffset, p.pageSize)
+		if err != nil {
+			return fmt.Errorf("fetch battles at offset %d: %w", checkpoint.NextOffset, err)
+		}
+
+		// Battles are sorted newest first, so anything older than since means we're done
+		inRange := make([]tasks.Battle, 0, len(battles))
+		for _, battle := range battles {
+			if battle.StartTime.Before(since) {
+				checkpoint.Completed = true
+				continue
+			}
+			inRange = append(inRange, battle)
+		}
+
+		if len(inRange) > 0 {
+			if err := p.storeBattles(inRange); err != nil {
+				return err
+			}
+			oldest := inRange[len(inRange)-1].StartTime
+			checkpoint.OldestStartTime = &oldest
+		}
+
+		if len(battles) == 0 {
+			checkpoint.Completed = true
+		}
+
+		checkpoint.NextOffset += p.pageSize
+		checkpoint.Battles += int64(len(inRange))
+
+		if err := p.postgres.SaveBattleboardBackfill(checkpoint); err != nil {
+			return fmt.Errorf("save backfill checkpoint: %w", err)
+		}
+
+		p.log.Info("battleboard backfill page processed", "offset", checkpoint.NextOffset, "battles", len(inRange),
+			"oldest_start_time", checkpoint.OldestStartTime)
+	}
+
+	p.log.Info("battleboard backfill completed", "since", since, "battles", checkpoint.Battles)
+	return nil
+}
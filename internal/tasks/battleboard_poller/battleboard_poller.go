@@ -87,39 +87,43 @@ func (p *BattleboardPoller) runBatch() {
 		return
 	}
 
-	summaries := p.collectBattleSummaries(allBattles)
-	allianceStats := p.collectBattleAllianceStats(allBattles)
-	guildStats := p.collectBattleGuildStats(allBattles)
-	playerStats := p.collectBattlePlayerStats(allBattles)
-	queues := p.collectBattleQueues(allBattles)
+	if err := p.storeBattles(allBattles); err != nil {
+		p.log.Error("failed to store battles", "error", err)
+	}
+}
+
+// storeBattles writes battleboard rows for the given battles and queues them for
+// event processing. Battles that are already stored are left untouched.
+func (p *BattleboardPoller) storeBattles(battles []tasks.Battle) error {
+	summaries := p.collectBattleSummaries(battles)
+	allianceStats := p.collectBattleAllianceStats(battles)
+	guildStats := p.collectBattleGuildStats(battles)
+	playerStats := p.collectBattlePlayerStats(battles)
+	queues := p.collectBattleQueues(battles)
 
 	if err := p.postgres.InsertBattleSummaries(summaries); err != nil {
-		p.log.Error("failed to insert battle summaries", "error", err)
-		return
+		return fmt.Errorf("insert battle summaries: %w", err)
 	}
 
 	if err := p.postgres.InsertBattleAllianceStats(allianceStats); err != nil {
-		p.log.Error("failed to insert battle alliance stats", "error", err)
-		return
+		return fmt.Errorf("insert battle alliance stats: %w", err)
 	}
 
 	if err := p.postgres.InsertBattleGuildStats(guildStats); err != nil {
-		p.log.Error("failed to insert battle guild stats", "error", err)
-		return
+		return fmt.Errorf("insert battle guild stats: %w", err)
 	}
 
 	if err := p.postgres.InsertBattlePlayerStats(playerStats); err != nil {
-		p.log.Error("failed to insert battle player stats", "error", err)
-		return
+		return fmt.Errorf("insert battle player stats: %w", err)
 	}
 
 	if err := p.postgres.InsertBattleQueues(queues); err != nil {
-		p.log.Error("failed to insert battle queues", "error", err)
-		return
+		return fmt.Errorf("insert battle queues: %w", err)
 	}
 
-	p.log.Info("battleboard polling completed", "battles", len(allBattles), "summaries", len(summaries),
-		"alliance_stats", len(allianceStats), "guild_stats", len(guildStats), "player_stats", len(playerStats), "queues", len(queues))
+	p.log.Info("battles stored", "battles", len(battles), "summaries", len(summaries), "alliance_stats", len(allianceStats),
+		"guild_stats", len(guildStats), "player_stats", len(playerStats), "queues", len(queues))
+	return nil
 }
 
 func (p *BattleboardPoller) fetchBattlesWithRetry(region string, offset, limit int) ([]tasks.Battle, error) {
@@ -199,6 +203,11 @@ func (p *BattleboardPoller) collectBattleSummaries(battles []tasks.Battle) []pos
 			playerNames = append(playerNames, player.Name)
 		}
 
+		var clusterName *string
+		if battle.ClusterName != nil {
+			clusterName = util.NullableString(*battle.ClusterName)
+		}
+
 		summary = append(summary, postgres.BattleSummary{
 			Region:        postgres.Region(p.region),
 			BattleID:      battle.ID,
@@ -207,6 +216,7 @@ func (p *BattleboardPoller) collectBattleSummaries(battles []tasks.Battle) []pos
 			TotalPlayers:  int32(len(battle.Players)),
 			TotalKills:    battle.TotalKills,
 			TotalFame:     battle.TotalFame,
+			ClusterName:   clusterName,
 			AllianceNames: allianceNames,
 			GuildNames:    guildNames,
 			PlayerNames:   playerNames,
@@ -231,6 +241,7 @@ func (p *BattleboardPoller) collectBattleAllianceStats(battles []tasks.Battle) [
 				Region:       postgres.Region(p.region),
 				BattleID:     battle.ID,
 				AllianceName: alliance.Name,
+				AllianceID:   util.NullableString(alliance.ID),
 				StartTime:    battle.StartTime,
 				PlayerCount:  int32(playerCount),
 				Kills:        alliance.Kills,
@@ -257,6 +268,7 @@ func (p *BattleboardPoller) collectBattleGuildStats(battles []tasks.Battle) []po
 				Region:       postgres.Region(p.region),
 				BattleID:     battle.ID,
 				GuildName:    guild.Name,
+				GuildID:      guild.ID,
 				AllianceName: guild.Alliance,
 				StartTime:    battle.StartTime,
 				PlayerCount:  int32(playerCount),
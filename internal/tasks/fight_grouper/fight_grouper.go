@@ -0,0 +1,231 @@
+package fight_grouper
+
+import (
+	"albionstats/internal/postgres"
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+type Config struct {
+	Interval time.Duration
+	// How far back to look for battles to group on each run
+	Lookback time.Duration
+	// Largest pause between two battles that still counts as the same fight
+	MaxGap time.Duration
+}
+
+// Grouper links battles that overlap in time and zone and share an alliance or guild
+// into fights, so split battles can be reviewed together.
+type Grouper struct {
+	db       *postgres.Postgres
+	interval time.Duration
+	lookback time.Duration
+	maxGap   time.Duration
+	log      *slog.Logger
+}
+
+func NewGrouper(db *postgres.Postgres, logger *slog.Logger, config Config) (*Grouper, error) {
+	if logger == nil {
+		return nil, fmt.Errorf("logger is required")
+	}
+	if config.Interval <= 0 {
+		return nil, fmt.Errorf("interval is required")
+	}
+	if config.Lookback <= 0 {
+		return nil, fmt.Errorf("lookback is required")
+	}
+	if config.MaxGap < 0 {
+		return nil, fmt.Errorf("max gap must not be negative")
+	}
+
+	return &Grouper{
+		db:       db,
+		interval: config.Interval,
+		lookback: config.Lookback,
+		maxGap:   config.MaxGap,
+		log:      logger.With("component", "fight_grouper"),
+	}, nil
+}
+
+func (g *Grouper) Run(ctx context.Context) {
+	g.log.Info("fight grouper started", "interval", g.interval, "lookback", g.lookback, "max_gap", g.maxGap)
+
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+
+	g.groupAll(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			g.log.Info("fight grouper stopped")
+			return
+		case <-ticker.C:
+			g.groupAll(ctx)
+		}
+	}
+}
+
+func (g *Grouper) groupAll(ctx context.Context) {
+	regions := []postgres.Region{postgres.RegionAmericas, postgres.RegionEurope, postgres.RegionAsia}
+	for _, region := range regions {
+		start := time.Now()
+
+		candidates, err := g.db.GetFightCandidates(ctx, region, start.Add(-g.lookback))
+		if err != nil {
+			g.log.Error("get fight candidates failed", "region", region, "err", err)
+			continue
+		}
+
+		battleIDs := make([]int64, 0, len(candidates))
+		for _, c := range candidates {
+			battleIDs = append(battleIDs, c.BattleID)
+		}
+
+		fights := groupBattles(region, candidates, g.maxGap)
+		if err := g.db.ReplaceBattleFights(ctx, region, battleIDs, fights); err != nil {
+			g.log.Error("replace battle fights failed", "region", region, "err", err)
+			continue
+		}
+
+		g.log.Info("fights grouped", "region", region, "battles", len(candidates), "grouped", len(fights),
+			"duration_ms", time.Since(start).Milliseconds())
+	}
+}
+
+// groupBattles links battles that are close in time, in the same known zone and share
+// at least one alliance or guild. Only battles that end up grouped
+// with another battle are returned. candidates must be sorted by start time.
+func groupBattles(region postgres.Region, candidates []postgres.FightCandidate, maxGap time.Duration) []postgres.BattleFight {
+	parent := make([]int, len(candidates))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range candidates {
+		a := candidates[i]
+		for j := i + 1; j < len(candidates); j++ {
+			b := candidates[j]
+			// Sorted by start time, so nothing after b can be close to a either
+			if b.StartTime.Sub(latestEnd(a)) > maxGap {
+				break
+			}
+			if !sameZone(a, b) || !shareParticipants(a, b) {
+				continue
+			}
+			parent[find(j)] = find(i)
+		}
+	}
+
+	// Keep the fight id from earlier runs so groups stay stable as the lookback window
+	// slides. If a group split, only the first part keeps the old id.
+	var roots []int
+	members := make(map[int][]int)
+	for i := range candidates {
+		root := find(i)
+		if _, ok := members[root]; !ok {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], i)
+	}
+
+	fightIDs := make(map[int]int64)
+	claimed := make(map[int64]bool)
+	for _, root := range roots {
+		if len(members[root]) < 2 {
+			continue
+		}
+		var previous *int64
+		for _, i := range members[root] {
+			if id := candidates[i].FightID; id != nil && !claimed[*id] && (previous == nil || *id < *previous) {
+				previous = id
+			}
+		}
+		if previous != nil {
+			fightIDs[root] = *previous
+			claimed[*previous] = true
+			continue
+		}
+		fightIDs[root] = lowestUnclaimed(candidates, members[root], claimed)
+		claimed[fightIDs[root]] = true
+	}
+
+	var fights []postgres.BattleFight
+	for i, c := range candidates {
+		root := find(i)
+		if len(members[root]) < 2 {
+			continue
+		}
+		fights = append(fights, postgres.BattleFight{
+			Region:    region,
+			BattleID:  c.BattleID,
+			FightID:   fightIDs[root],
+			StartTime: c.StartTime,
+		})
+	}
+	return fights
+}
+
+// lowestUnclaimed picks the lowest battle id in a group that no other fight uses yet.
+func lowestUnclaimed(candidates []postgres.FightCandidate, group []int, claimed map[int64]bool) int64 {
+	var lowest int64
+	found := false
+	for _, i := range group {
+		id := candidates[i].BattleID
+		if !claimed[id] && (!found || id < lowest) {
+			lowest = id
+			found = true
+		}
+	}
+	if !found {
+		// Only if other groups kept old fight ids matching every battle here
+		lowest = candidates[group[0]].BattleID
+	}
+	return lowest
+}
+
+func latestEnd(c postgres.FightCandidate) time.Time {
+	if c.EndTime.Before(c.StartTime) {
+		return c.StartTime
+	}
+	return c.EndTime
+}
+
+// sameZone only matches known zones. Treating an unknown zone as a wildcard would let
+// one battle chain fights from all over the region together.
+func sameZone(a, b postgres.FightCandidate) bool {
+	if a.ClusterName == nil || b.ClusterName == nil {
+		return false
+	}
+	return *a.ClusterName == *b.ClusterName
+}
+
+func shareParticipants(a, b postgres.FightCandidate) bool {
+	return intersects(a.Alliances, b.Alliances) || intersects(a.Guilds, b.Guilds)
+}
+
+func intersects(a, b []string) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return false
+	}
+	set := make(map[string]struct{}, len(a))
+	for _, v := range a {
+		set[v] = struct{}{}
+	}
+	for _, v := range b {
+		if _, ok := set[v]; ok {
+			return true
+		}
+	}
+	return false
+}
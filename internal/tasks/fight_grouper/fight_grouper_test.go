@@ -0,0 +1,185 @@
+package fight_grouper
+
+import (
+	"albionstats/internal/postgres"
+	"testing"
+	"time"
+)
+
+var t0 = time.Date(2025, 1, 1, 20, 0, 0, 0, time.UTC)
+
+func strPtr(s string) *string { return &s }
+
+func int64Ptr(v int64) *int64 { return &v }
+
+func candidate(id int64, startMin, endMin int, cluster *string, alliances ...string) postgres.FightCandidate {
+	return postgres.FightCandidate{
+		BattleID:    id,
+		StartTime:   t0.Add(time.Duration(startMin) * time.Minute),
+		EndTime:     t0.Add(time.Duration(endMin) * time.Minute),
+		ClusterName: cluster,
+		Alliances:   alliances,
+	}
+}
+
+func TestSameZone(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b *string
+		want bool
+	}{
+		{"both known and equal", strPtr("0000"), strPtr("0000"), true},
+		{"both known and different", strPtr("0000"), strPtr("1000"), false},
+		{"first unknown", nil, strPtr("0000"), false},
+		{"second unknown", strPtr("0000"), nil, false},
+		{"both unknown", nil, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := postgres.FightCandidate{ClusterName: tt.a}
+			b := postgres.FightCandidate{ClusterName: tt.b}
+			if got := sameZone(a, b); got != tt.want {
+				t.Errorf("sameZone() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupBattles(t *testing.T) {
+	zoneA, zoneB := strPtr("0000"), strPtr("1000")
+
+	tests := []struct {
+		name       string
+		candidates []postgres.FightCandidate
+		maxGap     time.Duration
+		// Expected fight id per battle id; battles left out must not be grouped
+		want map[int64]int64
+	}{
+		{
+			name: "overlapping battles in the same zone with a shared alliance",
+			candidates: []postgres.FightCandidate{
+				candidate(10, 0, 5, zoneA, "A", "B"),
+				candidate(11, 4, 9, zoneA, "B", "C"),
+			},
+			maxGap: time.Minute,
+			want:   map[int64]int64{10: 10, 11: 10},
+		},
+		{
+			name: "different zones are not grouped",
+			candidates: []postgres.FightCandidate{
+				candidate(10, 0, 5, zoneA, "A"),
+				candidate(11, 4, 9, zoneB, "A"),
+			},
+			maxGap: time.Minute,
+			want:   map[int64]int64{},
+		},
+		{
+			name: "an unknown zone does not chain fights together",
+			candidates: []postgres.FightCandidate{
+				candidate(10, 0, 5, zoneA, "A"),
+				candidate(11, 1, 6, nil, "A"),
+				candidate(12, 2, 7, zoneB, "A"),
+			},
+			maxGap: time.Minute,
+			want:   map[int64]int64{},
+		},
+		{
+			name: "no shared alliance or guild",
+			candidates: []postgres.FightCandidate{
+				candidate(10, 0, 5, zoneA, "A"),
+				candidate(11, 4, 9, zoneA, "B"),
+			},
+			maxGap: time.Minute,
+			want:   map[int64]int64{},
+		},
+		{
+			name: "gap larger than maxGap",
+			candidates: []postgres.FightCandidate{
+				candidate(10, 0, 5, zoneA, "A"),
+				candidate(11, 10, 15, zoneA, "A"),
+			},
+			maxGap: 2 * time.Minute,
+			want:   map[int64]int64{},
+		},
+		{
+			name: "gap within maxGap",
+			candidates: []postgres.FightCandidate{
+				candidate(10, 0, 5, zoneA, "A"),
+				candidate(11, 7, 12, zoneA, "A"),
+			},
+			maxGap: 2 * time.Minute,
+			want:   map[int64]int64{10: 10, 11: 10},
+		},
+		{
+			name: "fight id from an earlier run is kept",
+			candidates: func() []postgres.FightCandidate {
+				a := candidate(10, 0, 5, zoneA, "A")
+				b := candidate(11, 4, 9, zoneA, "A")
+				a.FightID, b.FightID = int64Ptr(7), int64Ptr(7)
+				return []postgres.FightCandidate{a, b}
+			}(),
+			maxGap: time.Minute,
+			want:   map[int64]int64{10: 7, 11: 7},
+		},
+		{
+			name: "only the first part of a split group keeps the old fight id",
+			candidates: func() []postgres.FightCandidate {
+				a := candidate(10, 0, 5, zoneA, "A")
+				b := candidate(11, 4, 9, zoneA, "A")
+				c := candidate(12, 5, 10, zoneB, "B")
+				d := candidate(13, 6, 11, zoneB, "B")
+				for _, fc := range []*postgres.FightCandidate{&a, &b, &c, &d} {
+					fc.FightID = int64Ptr(10)
+				}
+				return []postgres.FightCandidate{a, b, c, d}
+			}(),
+			maxGap: time.Minute,
+			want:   map[int64]int64{10: 10, 11: 10, 12: 12, 13: 12},
+		},
+		{
+			// The first battle started before the lookback window and is only a candidate
+			// because it shares a fight with the second
+			name: "a fight member from before the lookback window stays in the fight",
+			candidates: func() []postgres.FightCandidate {
+				a := candidate(10, -90, -55, zoneA, "A")
+				b := candidate(11, -56, -50, zoneA, "A")
+				a.FightID, b.FightID = int64Ptr(10), int64Ptr(10)
+				return []postgres.FightCandidate{a, b}
+			}(),
+			maxGap: time.Minute,
+			want:   map[int64]int64{10: 10, 11: 10},
+		},
+		{
+			// Neither battle is returned, so replacing the candidates' rows drops the
+			// whole fight rather than leaving the older battle in a fight of its own
+			name: "a fight that no longer groups returns none of its members",
+			candidates: func() []postgres.FightCandidate {
+				a := candidate(10, -90, -55, zoneA, "A")
+				b := candidate(11, -56, -50, zoneA, "B")
+				a.FightID, b.FightID = int64Ptr(10), int64Ptr(10)
+				return []postgres.FightCandidate{a, b}
+			}(),
+			maxGap: time.Minute,
+			want:   map[int64]int64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fights := groupBattles(postgres.RegionEurope, tt.candidates, tt.maxGap)
+
+			got := make(map[int64]int64, len(fights))
+			for _, f := range fights {
+				got[f.BattleID] = f.FightID
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("grouped %v, want %v", got, tt.want)
+			}
+			for battleID, fightID := range tt.want {
+				if got[battleID] != fightID {
+					t.Errorf("battle %d: fight %d, want %d", battleID, got[battleID], fightID)
+				}
+			}
+		})
+	}
+}
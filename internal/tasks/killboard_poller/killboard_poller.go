@@ -6,6 +6,7 @@ import (
 
 	"albionstats/internal/postgres"
 	"albionstats/internal/tasks"
+	"albionstats/internal/tasks/legendary_souls"
 	"albionstats/internal/util"
 )
 
@@ -78,6 +79,15 @@ func (p *KillboardPoller) runBatch() {
 		return
 	}
 
+	if err := p.postgres.InsertKillboardKills(p.openWorldKills(filteredEvents)); err != nil {
+		p.log.Error("insert killboard kills failed", "err", err)
+	}
+
+	souls, sightings := legendary_souls.Collect(p.region, filteredEvents)
+	if err := p.postgres.UpsertLegendarySouls(souls, sightings); err != nil {
+		p.log.Error("upsert legendary souls failed", "err", err, "souls", len(souls))
+	}
+
 	playerMap := make(map[string]postgres.PlayerPoll)
 	p.collectPlayers(filteredEvents, playerMap)
 
@@ -123,3 +133,49 @@ func (p *KillboardPoller) collectPlayers(events []tasks.Event, acc map[string]po
 		}
 	}
 }
+
+// openWorldKills keeps the events that aren't part of a battle, for small-scale stats.
+// The API gives a standalone kill its own event id as BattleId.
+func (p *KillboardPoller) openWorldKills(events []tasks.Event) []postgres.KillboardKill {
+	kills := make([]postgres.KillboardKill, 0, len(events))
+	for _, ev := range events {
+		if ev.BattleID != 0 && ev.BattleID != ev.EventID {
+			continue
+		}
+
+		participants := ev.NumberOfParticipants
+		if participants < 1 {
+			participants = int32(len(ev.Participants))
+		}
+
+		kills = append(kills, postgres.KillboardKill{
+			Region:         postgres.Region(p.region),
+			EventID:        ev.EventID,
+			TS:             ev.TimeStamp,
+			KillerID:       util.NullableString(ev.Killer.ID),
+			KillerName:     ev.Killer.Name,
+			KillerGuild:    util.NullableString(ev.Killer.GuildName),
+			KillerAlliance: util.NullableString(ev.Killer.AllianceName),
+			KillerIP:       int32(ev.Killer.AverageItemPower),
+			KillerWeapon:   mainHand(ev.Killer),
+			VictimID:       util.NullableString(ev.Victim.ID),
+			VictimName:     ev.Victim.Name,
+			VictimGuild:    util.NullableString(ev.Victim.GuildName),
+			VictimAlliance: util.NullableString(ev.Victim.AllianceName),
+			VictimIP:       int32(ev.Victim.AverageItemPower),
+			VictimWeapon:   mainHand(ev.Victim),
+			GroupSize:      ev.GroupMemberCount,
+			Participants:   participants,
+			Fame:           ev.TotalVictimKillFame,
+			KillArea:       util.NullableString(ev.KillArea),
+		})
+	}
+	return kills
+}
+
+func mainHand(participant tasks.Participant) *string {
+	if mh, ok := participant.Equipment["MainHand"]; ok && mh != nil {
+		return util.NullableString(mh.Type)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+// Package legendary_souls extracts legendary weapon souls from kill events, for the
+// killboard and battle pollers to store.
+package legendary_souls
+
+import (
+	"albionstats/internal/postgres"
+	"albionstats/internal/tasks"
+	"albionstats/internal/util"
+	"encoding/json"
+	"time"
+)
+
+// Collect returns the latest state of every soul carried by anyone in the events,
+// and one sighting per soul per event.
+func Collect(region string, events []tasks.Event) ([]postgres.LegendarySoul, []postgres.LegendarySoulSighting) {
+	latest := make(map[string]postgres.LegendarySoul)
+	firstSeen := make(map[string]time.Time)
+	type sightingKey struct {
+		soulID  string
+		eventID int64
+	}
+	seen := make(map[sightingKey]bool)
+	var sightings []postgres.LegendarySoulSighting
+
+	for _, event := range events {
+		add := func(holder tasks.Participant, item *tasks.EquipmentItem) {
+			if item == nil || item.LegendarySoul == nil || item.LegendarySoul.ID == "" {
+				return
+			}
+			soul := item.LegendarySoul
+
+			key := sightingKey{soul.ID, event.EventID}
+			if !seen[key] {
+				seen[key] = true
+				sightings = append(sightings, postgres.LegendarySoulSighting{
+					Region:            postgres.Region(region),
+					SoulID:            soul.ID,
+					EventID:           event.EventID,
+					TS:                event.TimeStamp,
+					HolderID:          util.NullableString(holder.ID),
+					HolderName:        holder.Name,
+					AttunedPlayerID:   util.NullableString(soul.AttunedPlayer),
+					AttunedPlayerName: util.NullableString(soul.AttunedPlayerName),
+					Attunement:        soul.Attunement,
+					AttunementSpent:   soul.AttunementSpent,
+					PvPFameGained:     soul.PvPFameGained,
+				})
+			}
+
+			if first, ok := firstSeen[soul.ID]; !ok || event.TimeStamp.Before(first) {
+				firstSeen[soul.ID] = event.TimeStamp
+			}
+			if existing, ok := latest[soul.ID]; ok && existing.LastSeen.After(event.TimeStamp) {
+				return
+			}
+
+			traits, err := json.Marshal(soul.Traits)
+			if err != nil || soul.Traits == nil {
+				traits = []byte("[]")
+			}
+
+			record := postgres.LegendarySoul{
+				Region:            postgres.Region(region),
+				SoulID:            soul.ID,
+				ItemType:          item.Type,
+				Subtype:           soul.Subtype,
+				Era:               soul.Era,
+				Name:              soul.Name,
+				Quality:           soul.Quality,
+				CraftedBy:         util.NullableString(soul.CraftedBy),
+				AttunedPlayerID:   util.NullableString(soul.AttunedPlayer),
+				AttunedPlayerName: util.NullableString(soul.AttunedPlayerName),
+				Attunement:        soul.Attunement,
+				AttunementSpent:   soul.AttunementSpent,
+				PvPFameGained:     soul.PvPFameGained,
+				Traits:            traits,
+				LastSeen:          event.TimeStamp,
+			}
+			if !soul.LastEquipped.IsZero() {
+				lastEquipped := soul.LastEquipped
+				record.LastEquipped = &lastEquipped
+			}
+			latest[soul.ID] = record
+		}
+
+		for _, participant := range allParticipants(event) {
+			for _, item := range participant.Equipment {
+				add(participant, item)
+			}
+			for _, item := range participant.Inventory {
+				add(participant, item)
+			}
+		}
+	}
+
+	souls := make([]postgres.LegendarySoul, 0, len(latest))
+	for id, soul := range latest {
+		soul.FirstSeen = firstSeen[id]
+		souls = append(souls, soul)
+	}
+	return souls, sightings
+}
+
+func allParticipants(event tasks.Event) []tasks.Participant {
+	all := make([]tasks.Participant, 0, 2+len(event.Participants)+len(event.GroupMembers))
+	all = append(all, event.Killer, event.Victim)
+	all = append(all, event.Participants...)
+	all = append(all, event.GroupMembers...)
+	return all
+}
@@ -0,0 +1,20 @@
+package tasks
+
+import "albionstats/internal/items"
+
+// SilverLost estimates what a victim dropped or destroyed: everything equipped plus
+// their inventory, at the prices in the price table.
+func (p Participant) SilverLost(prices *items.PriceTable) int64 {
+	var total int64
+	for _, item := range p.Equipment {
+		if item != nil {
+			total += prices.Value(item.Type, item.Quality, item.Count)
+		}
+	}
+	for _, item := range p.Inventory {
+		if item != nil {
+			total += prices.Value(item.Type, item.Quality, item.Count)
+		}
+	}
+	return total
+}
@@ -0,0 +1,57 @@
+package util
+
+// MVPWeights is the scoring model for picking a battle's MVP. Deaths count against
+// the score. IP scales the rest of the score by how far below the battle's average
+// item power the player was, so a win in cheaper gear counts for more; 0 turns the
+// adjustment off.
+type MVPWeights struct {
+	Kills    float64
+	Assists  float64
+	KillFame float64
+	Damage   float64
+	Healing  float64
+	Deaths   float64
+	IP       float64
+}
+
+// DefaultMVPWeights values a kill like 100k kill fame, 50k damage or 50k healing.
+var DefaultMVPWeights = MVPWeights{
+	Kills:    10,
+	Assists:  3,
+	KillFame: 0.0001,
+	Damage:   0.0002,
+	Healing:  0.0002,
+	Deaths:   8,
+	IP:       0.5,
+}
+
+// BattlePerformance is what a player did in one or more battles.
+type BattlePerformance struct {
+	Kills    int32
+	Assists  int32
+	Deaths   int32
+	KillFame int64
+	Damage   int64
+	Healing  int64
+	IP       int32
+}
+
+// Score rates a performance against the battle's average item power. Only what the
+// player contributed is scaled by gear; deaths cost the same in any gear, so a
+// player who only died scores below zero.
+func (w MVPWeights) Score(perf BattlePerformance, averageIP float64) float64 {
+	score := w.Kills*float64(perf.Kills) +
+		w.Assists*float64(perf.Assists) +
+		w.KillFame*float64(perf.KillFame) +
+		w.Damage*float64(perf.Damage) +
+		w.Healing*float64(perf.Healing)
+
+	if w.IP != 0 && averageIP > 0 && perf.IP > 0 {
+		factor := 1 + w.IP*(averageIP-float64(perf.IP))/averageIP
+		if factor < 0 {
+			factor = 0
+		}
+		score *= factor
+	}
+	return score - w.Deaths*float64(perf.Deaths)
+}
@@ -0,0 +1,97 @@
+package util
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMVPWeightsScore(t *testing.T) {
+	weights := MVPWeights{Kills: 10, Assists: 2, Deaths: 5, IP: 0.5}
+
+	tests := []struct {
+		name      string
+		weights   MVPWeights
+		perf      BattlePerformance
+		averageIP float64
+		want      float64
+	}{
+		{
+			name:      "average gear is not adjusted",
+			weights:   weights,
+			perf:      BattlePerformance{Kills: 2, Assists: 5, IP: 1000},
+			averageIP: 1000,
+			want:      30,
+		},
+		{
+			name:      "cheaper gear scales contributions up",
+			weights:   weights,
+			perf:      BattlePerformance{Kills: 2, IP: 800},
+			averageIP: 1000,
+			want:      22,
+		},
+		{
+			name:      "pricier gear scales contributions down",
+			weights:   weights,
+			perf:      BattlePerformance{Kills: 2, IP: 1200},
+			averageIP: 1000,
+			want:      18,
+		},
+		{
+			name:      "deaths are not scaled by gear",
+			weights:   weights,
+			perf:      BattlePerformance{Kills: 2, Deaths: 2, IP: 800},
+			averageIP: 1000,
+			want:      12,
+		},
+		{
+			name:      "only deaths scores below zero",
+			weights:   weights,
+			perf:      BattlePerformance{Deaths: 3, IP: 800},
+			averageIP: 1000,
+			want:      -15,
+		},
+		{
+			name:      "deaths outweighing contributions scores below zero",
+			weights:   weights,
+			perf:      BattlePerformance{Kills: 1, Deaths: 4, IP: 1000},
+			averageIP: 1000,
+			want:      -10,
+		},
+		{
+			name:      "factor is clamped at zero",
+			weights:   MVPWeights{Kills: 10, Deaths: 5, IP: 2},
+			perf:      BattlePerformance{Kills: 3, Deaths: 1, IP: 2000},
+			averageIP: 1000,
+			want:      -5,
+		},
+		{
+			name:      "zero average item power skips the adjustment",
+			weights:   weights,
+			perf:      BattlePerformance{Kills: 2, Deaths: 1, IP: 1200},
+			averageIP: 0,
+			want:      15,
+		},
+		{
+			name:      "unknown item power skips the adjustment",
+			weights:   weights,
+			perf:      BattlePerformance{Kills: 2, Deaths: 1},
+			averageIP: 1000,
+			want:      15,
+		},
+		{
+			name:      "zero IP weight skips the adjustment",
+			weights:   MVPWeights{Kills: 10, Deaths: 5},
+			perf:      BattlePerformance{Kills: 2, Deaths: 1, IP: 800},
+			averageIP: 1000,
+			want:      15,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.weights.Score(tt.perf, tt.averageIP)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Score() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -15,3 +15,28 @@ func IsValidServer(server string) bool {
 	}
 	return validServers[server]
 }
+
+// drawFameMargin is how close two sides' kill fame must be, as a share of the total,
+// for a battle to count as a draw.
+const drawFameMargin = 0.1
+
+// WinningSide compares the kill fame taken by sides 1 and 2. It returns nil if
+// neither side scored, and 0 if the sides are within drawFameMargin of each other.
+func WinningSide(sideOneFame, sideTwoFame int64) *int16 {
+	if sideOneFame == 0 && sideTwoFame == 0 {
+		return nil
+	}
+
+	var winner int16
+	diff := sideOneFame - sideTwoFame
+	if diff < 0 {
+		diff = -diff
+	}
+	if float64(diff) > drawFameMargin*float64(sideOneFame+sideTwoFame) {
+		winner = 1
+		if sideTwoFame > sideOneFame {
+			winner = 2
+		}
+	}
+	return &winner
+}
@@ -0,0 +1,43 @@
+package util
+
+import "testing"
+
+func TestWinningSide(t *testing.T) {
+	tests := []struct {
+		name    string
+		sideOne int64
+		sideTwo int64
+		want    *int16
+	}{
+		{name: "neither side scored", sideOne: 0, sideTwo: 0, want: nil},
+		{name: "only side one scored", sideOne: 100, sideTwo: 0, want: side(1)},
+		{name: "only side two scored", sideOne: 0, sideTwo: 100, want: side(2)},
+		{name: "side one clearly ahead", sideOne: 120, sideTwo: 80, want: side(1)},
+		{name: "side two clearly ahead", sideOne: 80, sideTwo: 120, want: side(2)},
+		{name: "within the draw margin", sideOne: 105, sideTwo: 95, want: side(0)},
+		{name: "exactly on the draw margin", sideOne: 110, sideTwo: 90, want: side(0)},
+		{name: "just past the draw margin", sideOne: 111, sideTwo: 89, want: side(1)},
+		{name: "equal fame", sideOne: 500, sideTwo: 500, want: side(0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WinningSide(tt.sideOne, tt.sideTwo)
+			switch {
+			case got == nil && tt.want == nil:
+			case got == nil || tt.want == nil:
+				t.Fatalf("WinningSide(%d, %d) = %v, want %v", tt.sideOne, tt.sideTwo, deref(got), deref(tt.want))
+			case *got != *tt.want:
+				t.Errorf("WinningSide(%d, %d) = %d, want %d", tt.sideOne, tt.sideTwo, *got, *tt.want)
+			}
+		})
+	}
+}
+
+func side(s int16) *int16 { return &s }
+
+func deref(s *int16) interface{} {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
@@ -0,0 +1,59 @@
+package zones
+
+import (
+	"albionstats/internal/postgres"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Load reads the zone catalogue from a CSV file with the header cluster_id,name,type,
+// where type is one of blue, yellow, red, black or avalon_road.
+func Load(path string) ([]postgres.Zone, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open zones file: %w", err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = 3
+	r.TrimLeadingSpace = true
+
+	if _, err := r.Read(); err != nil {
+		return nil, fmt.Errorf("read zones header: %w", err)
+	}
+
+	var zones []postgres.Zone
+	seen := make(map[string]bool)
+	for line := 2; ; line++ {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read zones line %d: %w", line, err)
+		}
+
+		clusterID := strings.TrimSpace(record[0])
+		name := strings.TrimSpace(record[1])
+		zoneType := strings.ToLower(strings.TrimSpace(record[2]))
+		if clusterID == "" || name == "" {
+			return nil, fmt.Errorf("zones line %d: cluster id and name are required", line)
+		}
+		if !postgres.IsValidZoneType(zoneType) {
+			return nil, fmt.Errorf("zones line %d: invalid type %q", line, zoneType)
+		}
+		if seen[clusterID] {
+			return nil, fmt.Errorf("zones line %d: duplicate cluster id %q", line, clusterID)
+		}
+		seen[clusterID] = true
+
+		zones = append(zones, postgres.Zone{ClusterID: clusterID, Name: name, Type: postgres.ZoneType(zoneType)})
+	}
+
+	return zones, nil
+}
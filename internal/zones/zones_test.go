@@ -0,0 +1,73 @@
+package zones
+
+import (
+	"albionstats/internal/postgres"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeZones(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "zones.csv")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeZones(t, `cluster_id,name,type
+0007,Thetford,blue
+ 1002 , Lymhurst , BLUE
+`)
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := []postgres.Zone{
+		{ClusterID: "0007", Name: "Thetford", Type: postgres.ZoneTypeBlue},
+		{ClusterID: "1002", Name: "Lymhurst", Type: postgres.ZoneTypeBlue},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Load() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("zone %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLoadExample(t *testing.T) {
+	if _, err := Load(filepath.Join("..", "..", "zones.example.csv")); err != nil {
+		t.Fatalf("Load(zones.example.csv) error = %v", err)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+	}{
+		{"empty file", ""},
+		{"wrong field count", "cluster_id,name,type\n0007,Thetford\n"},
+		{"unknown type", "cluster_id,name,type\n0007,Thetford,green\n"},
+		{"missing cluster id", "cluster_id,name,type\n,Thetford,blue\n"},
+		{"missing name", "cluster_id,name,type\n0007,,blue\n"},
+		{"duplicate cluster id", "cluster_id,name,type\n0007,Thetford,blue\n0007,Thetford,blue\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(writeZones(t, tt.contents)); err == nil {
+				t.Error("Load() error = nil, want an error")
+			}
+		})
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.csv")); err == nil {
+		t.Error("Load() of a missing file error = nil, want an error")
+	}
+}
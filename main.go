@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"log/slog"
@@ -13,14 +14,20 @@ import (
 
 	"albionstats/internal/api"
 	"albionstats/internal/config"
+	"albionstats/internal/items"
 	"albionstats/internal/postgres"
 	"albionstats/internal/tasks"
 	"albionstats/internal/tasks/battle_poller"
 	"albionstats/internal/tasks/battleboard_poller"
 	"albionstats/internal/tasks/data_purger"
+	"albionstats/internal/tasks/fight_grouper"
 	"albionstats/internal/tasks/killboard_poller"
 	"albionstats/internal/tasks/metrics_collector"
 	"albionstats/internal/tasks/player_poller"
+	"albionstats/internal/util"
+	"albionstats/internal/zones"
+
+	"golang.org/x/sync/errgroup"
 )
 
 func main() {
@@ -59,9 +66,39 @@ func main() {
 	ctx, cancel := signalContext(context.Background())
 	defer cancel()
 
+	if len(os.Args) > 1 && os.Args[1] == "backfill" {
+		if err := runBackfill(ctx, cfg, apiClient, postgres, appLogger, os.Args[2:]); err != nil {
+			log.Fatalf("battleboard backfill: %v", err)
+		}
+		return
+	}
+
+	// The catalogue only adds names and categories, so run without it if it's missing
+	itemCatalogue, err := items.Load(cfg.ItemsFile)
+	if err != nil {
+		appLogger.Warn("item catalogue not loaded", "path", cfg.ItemsFile, "err", err)
+	}
+
+	// Without prices, silver lost is recorded as 0
+	prices, err := items.LoadPrices(cfg.PricesFile)
+	if err != nil {
+		appLogger.Warn("item prices not loaded", "path", cfg.PricesFile, "err", err)
+	}
+
+	// Without zones, battles still store their cluster but have no zone name or type
+	if zoneCatalogue, err := zones.Load(cfg.ZonesFile); err != nil {
+		appLogger.Warn("zone catalogue not loaded", "path", cfg.ZonesFile, "err", err)
+	} else if err := postgres.UpsertZones(ctx, zoneCatalogue); err != nil {
+		appLogger.Warn("zone catalogue not stored", "path", cfg.ZonesFile, "err", err)
+	}
+
 	server := api.NewServer(api.Config{
-		Postgres: postgres,
-		Logger:   appLogger,
+		Postgres:   postgres,
+		APIClient:  apiClient,
+		Logger:     appLogger,
+		Items:      itemCatalogue,
+		Prices:     prices,
+		MVPWeights: cfg.MVPWeights,
 	})
 
 	go func() {
@@ -96,6 +133,20 @@ func main() {
 		dataPurger.Run(ctx)
 	}()
 
+	// Start fight grouper
+	fightGrouper, err := fight_grouper.NewGrouper(postgres, appLogger, fight_grouper.Config{
+		Interval: 10 * time.Minute,
+		Lookback: 24 * time.Hour,
+		MaxGap:   10 * time.Minute,
+	})
+	if err != nil {
+		log.Fatalf("fight grouper init: %v", err)
+	}
+
+	go func() {
+		fightGrouper.Run(ctx)
+	}()
+
 	// Start player pollers for all regions
 	regions := []string{"americas", "europe", "asia"}
 	for _, region := range regions {
@@ -159,10 +210,13 @@ func main() {
 	// Start battle poller for all regions
 	for _, region := range regions {
 		battlePoller := battle_poller.NewBattlePoller(battle_poller.Config{
-			Region:    region,
-			APIClient: apiClient,
-			Postgres:  postgres,
-			Logger:    appLogger,
+			Region:     region,
+			APIClient:  apiClient,
+			Postgres:   postgres,
+			Logger:     appLogger,
+			Prices:     prices,
+			Items:      itemCatalogue,
+			MVPWeights: cfg.MVPWeights,
 		})
 
 		go func(poller *battle_poller.BattlePoller, regionName string) {
@@ -176,6 +230,53 @@ func main() {
 	log.Printf("shutdown complete")
 }
 
+// runBackfill walks the battleboards back to -since for each region and exits.
+// Usage: albionstats backfill -since 2025-01-31 [-region europe]
+func runBackfill(ctx context.Context, cfg config.Config, apiClient *tasks.Client, db *postgres.Postgres, logger *slog.Logger, args []string) error {
+	fs := flag.NewFlagSet("backfill", flag.ExitOnError)
+	sinceStr := fs.String("since", "", "backfill battles started at or after this time (RFC3339 or YYYY-MM-DD)")
+	region := fs.String("region", "", "region to backfill (default: all regions)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	since, err := time.Parse(time.RFC3339, *sinceStr)
+	if err != nil {
+		since, err = time.Parse(time.DateOnly, *sinceStr)
+		if err != nil {
+			return fmt.Errorf("invalid -since %q: must be RFC3339 or YYYY-MM-DD", *sinceStr)
+		}
+	}
+
+	regions := []string{"americas", "europe", "asia"}
+	if *region != "" {
+		if !util.IsValidServer(*region) {
+			return fmt.Errorf("invalid -region %q", *region)
+		}
+		regions = []string{*region}
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	for _, region := range regions {
+		poller := battleboard_poller.NewBattleboardPoller(battleboard_poller.Config{
+			APIClient: apiClient,
+			Postgres:  db,
+			Logger:    logger,
+			Region:    region,
+			PageSize:  cfg.BattleboardPageSize,
+		})
+
+		g.Go(func() error {
+			if err := poller.Backfill(ctx, since.UTC()); err != nil {
+				return fmt.Errorf("%s: %w", region, err)
+			}
+			return nil
+		})
+	}
+
+	return g.Wait()
+}
+
 func signalContext(parent context.Context) (context.Context, context.CancelFunc) {
 	ctx, cancel := context.WithCancel(parent)
 	sigCh := make(chan os.Signal, 1)
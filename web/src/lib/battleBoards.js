@@ -8,16 +8,32 @@ export function mapEntries(list = []) {
 	});
 }
 
+export function mapSides(list = []) {
+	return (list || []).map((side) => ({
+		label: side.Name,
+		count: side.PlayerCount
+	}));
+}
+
 export function mapBattleBoardsData(data) {
 	if (!Array.isArray(data)) return [];
 	return data.map((battle) => ({
 		...battle,
-		AllianceEntries: mapEntries(battle.AllianceNames),
-		GuildEntries: mapEntries(battle.GuildNames)
+		AllianceEntries: battle.Alliances
+			? mapSides(battle.Alliances)
+			: mapEntries(battle.AllianceNames),
+		GuildEntries: battle.Guilds ? mapSides(battle.Guilds) : mapEntries(battle.GuildNames)
 	}));
 }
 
-export function buildBattleBoardsUrl({ base, region, type, q, p, offset }) {
+// The API returns the cursor for the next page in this header, and omits it on the last page.
+export const nextCursorHeader = 'X-Next-Cursor';
+
+export function readNextCursor(response) {
+	return response.headers.get(nextCursorHeader) || null;
+}
+
+export function buildBattleBoardsUrl({ base, region, type, q, p, offset, cursor }) {
 	let url;
 	const query = q || '';
 
@@ -35,7 +51,9 @@ export function buildBattleBoardsUrl({ base, region, type, q, p, offset }) {
 		url.searchParams.set('totalPlayers', p || '10');
 	}
 
-	if (offset > 0) {
+	if (cursor) {
+		url.searchParams.set('cursor', cursor);
+	} else if (offset > 0) {
 		url.searchParams.set('offset', offset.toString());
 	}
 
@@ -1,15 +1,15 @@
 import { error } from '@sveltejs/kit';
 import { getApiBase } from '$lib/apiBase';
-import { buildBattleBoardsUrl, mapBattleBoardsData } from '$lib/battleBoards';
+import { buildBattleBoardsUrl, mapBattleBoardsData, readNextCursor } from '$lib/battleBoards';
 import { validRegions } from '$lib/utils';
 const validTypes = new Set(['alliance', 'guild', 'player']);
 
-async function fetchJson(fetch, url) {
+async function fetchBoards(fetch, url) {
 	const response = await fetch(url);
 	if (!response.ok) {
 		throw new Error(`HTTP error! status: ${response.status}`);
 	}
-	return response.json();
+	return { data: await response.json(), cursor: readNextCursor(response) };
 }
 
 export const load = async ({ params, url, fetch }) => {
@@ -21,6 +21,7 @@ export const load = async ({ params, url, fetch }) => {
 
 	let initialBattles = [];
 	let initialHasMore = false;
+	let initialCursor = null;
 	let initialError = null;
 
 	if (!validRegions.has(region)) {
@@ -36,9 +37,10 @@ export const load = async ({ params, url, fetch }) => {
 				offset: 0
 			});
 
-			const data = await fetchJson(fetch, apiUrl.toString());
+			const { data, cursor } = await fetchBoards(fetch, apiUrl.toString());
 			initialBattles = mapBattleBoardsData(data);
-			initialHasMore = initialBattles.length >= 20;
+			initialCursor = cursor;
+			initialHasMore = cursor !== null;
 		} catch (err) {
 			initialError = 'No results found by that criteria.';
 		}
@@ -50,6 +52,7 @@ export const load = async ({ params, url, fetch }) => {
 		p,
 		initialBattles,
 		initialHasMore,
+		initialCursor,
 		initialError
 	};
 };